@@ -0,0 +1,124 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const appMeshLogGroupPatternVar = "APPMESH_LOG_GROUP_PATTERN"
+
+var appMeshLogGroups = newLogGroupFilter(appMeshLogGroupPatternVar, "/aws/appmesh/")
+
+// App Mesh Envoy access log entry in JSON format
+type appMeshAccessLog struct {
+	StartTime               string `json:"start_time"`
+	Method                  string `json:"method"`
+	Path                    string `json:"path"`
+	Protocol                string `json:"protocol"`
+	ResponseCode            int    `json:"response_code"`
+	ResponseDuration        int    `json:"response_duration"`
+	RequestId               string `json:"request_id"`
+	UpstreamCluster         string `json:"upstream_cluster"`
+	BytesReceived           int    `json:"bytes_received"`
+	BytesSent               int    `json:"bytes_sent"`
+	DownstreamRemoteAddress string `json:"downstream_remote_address"`
+	UpstreamHost            string `json:"upstream_host"`
+}
+
+func isAppMeshAccessLog(logGroup string, jsonEvent map[string]interface{}) bool {
+	return appMeshLogGroups.match(logGroup) &&
+		testJsonPath(jsonEvent, "upstream_cluster") &&
+		testJsonPath(jsonEvent, "downstream_remote_address")
+}
+
+func (evt *appMeshAccessLog) getInstanceId() (result string, err error) {
+	err = errors.New("App Mesh access log doesn't contain EC2 Instance ID")
+	return
+}
+
+func (evt *appMeshAccessLog) getRegion() (result string) {
+	result = lambdaRegion
+	return
+}
+
+func (evt *appMeshAccessLog) getEventType() (result string) {
+	result = ec2Event
+	return
+}
+
+func (evt *appMeshAccessLog) getBody() (result string) {
+	return
+}
+
+func (evt *appMeshAccessLog) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	switch {
+	case evt.ResponseCode >= 500:
+		severity = pdata.SeverityNumberERROR
+		severityText = "ERROR"
+	case evt.ResponseCode >= 400:
+		severity = pdata.SeverityNumberWARN
+		severityText = "WARN"
+	}
+	return
+}
+
+func (evt *appMeshAccessLog) getAttributes() (result map[string]interface{}) {
+	result = map[string]interface{}{
+		"http.status_code": evt.ResponseCode,
+	}
+	if evt.Method != "" {
+		result["http.method"] = evt.Method
+	}
+	if evt.Path != "" {
+		result["http.target"] = evt.Path
+	}
+	if flavor := evt.getHttpFlavor(); flavor != "" {
+		result["http.flavor"] = flavor
+	}
+	if peerIp := evt.getDownstreamIp(); peerIp != "" {
+		result["net.peer.ip"] = peerIp
+	}
+	if evt.UpstreamCluster != "" {
+		result["rpc.service"] = evt.UpstreamCluster
+	}
+	return
+}
+
+// protocol is reported as e.g. "HTTP/1.1" or "HTTP/2"
+func (evt *appMeshAccessLog) getHttpFlavor() string {
+	flavor := strings.TrimPrefix(evt.Protocol, "HTTP/")
+	if flavor == evt.Protocol {
+		return ""
+	}
+	if !strings.Contains(flavor, ".") {
+		flavor += ".0"
+	}
+	return flavor
+}
+
+// downstream remote address is reported as "ip:port"
+func (evt *appMeshAccessLog) getDownstreamIp() string {
+	host, _, err := net.SplitHostPort(evt.DownstreamRemoteAddress)
+	if err != nil {
+		return evt.DownstreamRemoteAddress
+	}
+	return host
+}
@@ -0,0 +1,96 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestAppMeshAccessLogDetection(t *testing.T) {
+	ok, result := parseMessage("/aws/appmesh/orders-mesh", `{"upstream_cluster":"cds_egress_orders-mesh_orders-service_http_8080","downstream_remote_address":"10.0.1.12:45678","response_code":200}`)
+	assert.True(t, ok)
+	assert.IsType(t, &appMeshAccessLog{}, result)
+
+	ok, _ = parseMessage("/aws/appmesh/orders-mesh", `{"upstream_cluster":"cds_egress_orders-mesh_orders-service_http_8080","response_code":200}`)
+	assert.False(t, ok)
+
+	ok, _ = parseMessage("/aws/lambda/my-function", `{"upstream_cluster":"cds_egress_orders-mesh_orders-service_http_8080","downstream_remote_address":"10.0.1.12:45678","response_code":200}`)
+	assert.False(t, ok)
+}
+
+func TestAppMeshAccessLogHttpFlavor(t *testing.T) {
+	testCases := []struct {
+		protocol string
+		flavor   string
+	}{
+		{"HTTP/1.1", "1.1"},
+		{"HTTP/2", "2.0"},
+		{"-", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.protocol, func(t *testing.T) {
+			accessLog := appMeshAccessLog{Protocol: tc.protocol}
+			assert.Equal(t, tc.flavor, accessLog.getHttpFlavor())
+		})
+	}
+}
+
+func TestLogEventsTransformAppMeshAccessLog(t *testing.T) {
+	data, err := os.ReadFile("testdata/appmesh_access_log.txt")
+	assert.Nil(t, err)
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        "1",
+			Timestamp: time.Now().UnixMilli(),
+			Message:   line,
+		})
+	}
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws/appmesh/orders-mesh", "envoy/orders-service/6f1c2b3a", logEvents, output, nil, nil)
+	logs := <-output
+
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, len(logEvents), logRecords.Len())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, logRecords.At(0).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberWARN, logRecords.At(1).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberERROR, logRecords.At(2).SeverityNumber())
+
+	attributes := logRecords.At(0).Attributes()
+	statusCode, ok := attributes.Get("http.status_code")
+	assert.True(t, ok)
+	assert.Equal(t, int64(200), statusCode.IntVal())
+	assertLogRecordHasAttribute(t, attributes, "http.method", "GET")
+	assertLogRecordHasAttribute(t, attributes, "http.target", "/api/orders/1001")
+	assertLogRecordHasAttribute(t, attributes, "http.flavor", "1.1")
+	assertLogRecordHasAttribute(t, attributes, "net.peer.ip", "10.0.1.12")
+	assertLogRecordHasAttribute(t, attributes, "rpc.service", "cds_egress_orders-mesh_orders-service_http_8080")
+	assertLogRecordHasAttribute(t, logRecords.At(1).Attributes(), "http.flavor", "2.0")
+
+	for range output {
+	}
+}
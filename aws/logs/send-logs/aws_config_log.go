@@ -0,0 +1,96 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+)
+
+const (
+	awsConfigLogGroupPatternVar = "AWS_CONFIG_LOG_GROUP_PATTERN"
+	awsConfigItemDeleted        = "ResourceDeleted"
+	awsConfigItemDeletedLegacy  = "ConfigurationItemDeleted"
+	awsConfigEc2InstanceType    = "AWS::EC2::Instance"
+)
+
+// Config change notifications reach CloudWatch Logs through EventBridge rules or SNS subscriptions
+// writing to log groups of any name, so detection is enabled only when the pattern is configured
+var awsConfigLogGroups = newRequiredLogGroupFilter(awsConfigLogGroupPatternVar)
+
+// AWS Config configuration change notification delivered to CloudWatch Logs
+type awsConfigLog struct {
+	ConfigurationItemStatus      string          `json:"configurationItemStatus"`
+	ConfigurationItemCaptureTime string          `json:"configurationItemCaptureTime"`
+	ResourceType                 string          `json:"resourceType"`
+	ResourceId                   string          `json:"resourceId"`
+	Region                       string          `json:"awsRegion"`
+	Configuration                json.RawMessage `json:"configuration"`
+}
+
+func isAwsConfigLog(logGroup string, jsonEvent map[string]interface{}) bool {
+	return awsConfigLogGroups.match(logGroup) &&
+		testJsonPath(jsonEvent, "configurationItemStatus") &&
+		testJsonPath(jsonEvent, "resourceType")
+}
+
+func (evt *awsConfigLog) getInstanceId() (result string, err error) {
+	if evt.ResourceType == awsConfigEc2InstanceType && evt.ResourceId != "" {
+		result = evt.ResourceId
+		return
+	}
+	err = errors.New("Configuration item is not an EC2 instance")
+	return
+}
+
+func (evt *awsConfigLog) getRegion() (result string) {
+	result = evt.Region
+	return
+}
+
+func (evt *awsConfigLog) getEventType() (result string) {
+	result = ec2Event
+	return
+}
+
+func (evt *awsConfigLog) getBody() (result string) {
+	if len(evt.Configuration) > 0 && string(evt.Configuration) != "null" {
+		result = string(evt.Configuration)
+	}
+	return
+}
+
+func (evt *awsConfigLog) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	if evt.ConfigurationItemStatus == awsConfigItemDeleted || evt.ConfigurationItemStatus == awsConfigItemDeletedLegacy {
+		severity = pdata.SeverityNumberWARN
+		severityText = "WARN"
+	}
+	return
+}
+
+func (evt *awsConfigLog) getAttributes() (result map[string]interface{}) {
+	result = map[string]interface{}{
+		"cloud.resource_type":          evt.ResourceType,
+		"cloud.resource_id":            evt.ResourceId,
+		"aws.config.item_status":       evt.ConfigurationItemStatus,
+		"aws.config.item_capture_time": evt.ConfigurationItemCaptureTime,
+		semconv.AttributeCloudRegion:   evt.Region,
+	}
+	return
+}
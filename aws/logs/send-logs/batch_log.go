@@ -0,0 +1,84 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+const batchLogGroupPatternVar = "BATCH_LOG_GROUP_PATTERN"
+
+var (
+	batchLogGroups          = newLogGroupFilter(batchLogGroupPatternVar, "/aws/batch/job")
+	detectBatchLogStream    = regexp.MustCompile(`^(?P<JobDefinition>[^/]+)/default/(?P<JobId>[^/]+)$`)
+	batchJobDefinitionIndex = detectBatchLogStream.SubexpIndex("JobDefinition")
+	batchJobIdIndex         = detectBatchLogStream.SubexpIndex("JobId")
+)
+
+// AWS Batch job log, the log stream is named <job-definition-name>/default/<job-id>
+type batchLogSource struct {
+	jobDefinitionName string
+	jobId             string
+}
+
+func newBatchLogSource(logGroup, logStream string) (source *batchLogSource, ok bool) {
+	if !batchLogGroups.match(logGroup) {
+		return
+	}
+
+	matches := detectBatchLogStream.FindStringSubmatch(logStream)
+	if matches == nil {
+		return
+	}
+
+	source = &batchLogSource{
+		jobDefinitionName: matches[batchJobDefinitionIndex],
+		jobId:             matches[batchJobIdIndex],
+	}
+	ok = true
+	return
+}
+
+func (src *batchLogSource) setResourceAttributes(reqBuilder OtlpRequestBuilder) OtlpRequestBuilder {
+	return reqBuilder.
+		SetBatchJobDefinitionName(src.jobDefinitionName).
+		SetBatchJobId(src.jobId)
+}
+
+func (src *batchLogSource) addLogEntry(reqBuilder OtlpRequestBuilder, itemId string, timestamp int64, message string) OtlpRequestBuilder {
+	attributes := getBatchLogAttributes(message)
+	if len(attributes) == 0 {
+		return reqBuilder.AddLogEntry(itemId, timestamp, message, lambdaRegion)
+	}
+	return reqBuilder.AddLogEntry(itemId, timestamp, message, lambdaRegion, attributes)
+}
+
+// Top-level string fields of a JSON formatted job log line
+func getBatchLogAttributes(message string) (result map[string]interface{}) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(message), &fields); err != nil {
+		return
+	}
+
+	result = make(map[string]interface{})
+	for key, value := range fields {
+		if str, ok := value.(string); ok {
+			result[key] = str
+		}
+	}
+	return
+}
@@ -0,0 +1,88 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestLogGroupPattern_BatchDetection(t *testing.T) {
+	testCases := []struct {
+		logGroup          string
+		logStream         string
+		ok                bool
+		jobDefinitionName string
+		jobId             string
+	}{
+		{"/aws/batch/job", "my-job-definition/default/2c8f3e1a9b7d4c6e8f0a1b2c3d4e5f60", true, "my-job-definition", "2c8f3e1a9b7d4c6e8f0a1b2c3d4e5f60"},
+		{"/aws/batch/job", "etl_nightly-v2/default/0f1e2d3c-4b5a-6978-8695-a4b3c2d1e0f9", true, "etl_nightly-v2", "0f1e2d3c-4b5a-6978-8695-a4b3c2d1e0f9"},
+		{"/aws/batch/job", "my-job-definition/2c8f3e1a9b7d4c6e8f0a1b2c3d4e5f60", false, "", ""},
+		{"/aws/batch/job", "my-job-definition/default/", false, "", ""},
+		{"/aws/lambda/my-function", "my-job-definition/default/2c8f3e1a9b7d4c6e8f0a1b2c3d4e5f60", false, "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.logGroup+":"+tc.logStream, func(t *testing.T) {
+			source, ok := newBatchLogSource(tc.logGroup, tc.logStream)
+			assert.Equal(t, tc.ok, ok)
+			if ok {
+				assert.Equal(t, tc.jobDefinitionName, source.jobDefinitionName)
+				assert.Equal(t, tc.jobId, source.jobId)
+			}
+		})
+	}
+}
+
+func TestLogEventsTransformBatchJobLog(t *testing.T) {
+	data, err := os.ReadFile("testdata/batch_job.txt")
+	assert.Nil(t, err)
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        "1",
+			Timestamp: time.Now().Unix(),
+			Message:   line,
+		})
+	}
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws/batch/job", "my-job-definition/default/2c8f3e1a9b7d4c6e8f0a1b2c3d4e5f60", logEvents, output, nil, nil)
+	logs := <-output
+
+	resourceAttributes := logs.ResourceLogs().At(0).Resource().Attributes()
+	assertLogRecordHasAttribute(t, resourceAttributes, "aws.batch.job_definition_name", "my-job-definition")
+	assertLogRecordHasAttribute(t, resourceAttributes, "aws.batch.job_id", "2c8f3e1a9b7d4c6e8f0a1b2c3d4e5f60")
+
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, len(logEvents), logRecords.Len())
+	assertLogRecordHasAttribute(t, logRecords.At(0).Attributes(), "msg", "processing chunk")
+	assertLogRecordHasAttribute(t, logRecords.At(0).Attributes(), "input", "s3://my-bucket/input/part-0003.csv")
+	assertLogRecordDoNotHaveAttribute(t, logRecords.At(0).Attributes(), "chunk")
+	assert.Equal(t, "Processed 10000 records in 12.4s", logRecords.At(1).Body().StringVal())
+	assertLogRecordDoNotHaveAttribute(t, logRecords.At(1).Attributes(), "msg")
+
+	for range output {
+	}
+}
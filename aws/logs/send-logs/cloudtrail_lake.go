@@ -0,0 +1,261 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"go.opentelemetry.io/collector/model/pdata"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	cloudTrailLakeLogGroupNameVar  = "CLOUDTRAIL_LAKE_LOG_GROUP_NAME"
+	cloudTrailLakeQueryFormatVar   = "CLOUDTRAIL_LAKE_QUERY_FORMAT"
+	cloudTrailLakeQueryFormatJson  = "json"
+	cloudTrailLakeQueryFormatCsv   = "csv"
+	cloudTrailLakeEventIdAttribute = "aws.cloudtrail.event_id"
+)
+
+var (
+	// log group name reported for CloudTrail Lake query results, the function handles S3 notifications of query results when set
+	cloudTrailLakeLogGroupName = os.Getenv(cloudTrailLakeLogGroupNameVar)
+	cloudTrailLakeQueryFormat  = strings.ToLower(os.Getenv(cloudTrailLakeQueryFormatVar))
+	cloudTrailLakeTimeLayouts  = []string{time.RFC3339Nano, "2006-01-02 15:04:05.000", "2006-01-02 15:04:05"}
+	getS3Object                = func(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+		output, err := s3.New(session.New()).GetObjectWithContext(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return output.Body, nil
+	}
+)
+
+// HandleCloudTrailLakeEvent forwards the results of a CloudTrail Lake scheduled query delivered to S3
+func HandleCloudTrailLakeEvent(ctx context.Context, event events.S3Event) (r string, err error) {
+	r = "failure"
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			appLogger.Error("PANIC in HandleCloudTrailLakeEvent: ", recovered, "\n", string(debug.Stack()))
+			r = "failure"
+			err = panicError(recovered)
+		}
+	}()
+
+	if err = validateCloudTrailLakeQueryFormat(); err != nil {
+		appLogger.Error(err.Error())
+		return r, err
+	}
+
+	conn, err := getClientConn(endpoint)
+	if err != nil {
+		appLogger.Error("While connecting to otlp/gRPC endpoint: ", err.Error())
+		return r, err
+	}
+	logsClient := newLogsClient(conn)
+
+	errs := make([]error, 0)
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+apiToken)
+
+	for _, record := range event.Records {
+		rows, err := readCloudTrailLakeResults(ctx, record.S3.Bucket.Name, record.S3.Object.URLDecodedKey)
+		if err != nil {
+			appLogger.Error("While reading CloudTrail Lake query results: ", err.Error())
+			errs = append(errs, err)
+			continue
+		}
+
+		logsChan := make(chan pdata.Logs)
+		go transformCloudTrailLakeRows(ctx, cloudTrailLakeLogGroupName, record.S3.Object.URLDecodedKey, rows, logsChan)
+		errs = append(errs, exportLogs(ctx, logsClient, logsChan)...)
+	}
+
+	if len(errs) == 0 {
+		r = "success"
+	} else {
+		err = errs[len(errs)-1]
+	}
+	appLogger.Info("Function execution result: ", r)
+
+	return r, err
+}
+
+// query result row, the body holds the original JSON object or CSV row text
+type cloudTrailLakeRow struct {
+	fields map[string]interface{}
+	body   string
+}
+
+// the query results format is json when CLOUDTRAIL_LAKE_QUERY_FORMAT is not set
+func validateCloudTrailLakeQueryFormat() error {
+	switch cloudTrailLakeQueryFormat {
+	case "", cloudTrailLakeQueryFormatJson, cloudTrailLakeQueryFormatCsv:
+		return nil
+	}
+	return fmt.Errorf("Invalid %s value %q, expected %s or %s", cloudTrailLakeQueryFormatVar, cloudTrailLakeQueryFormat, cloudTrailLakeQueryFormatJson, cloudTrailLakeQueryFormatCsv)
+}
+
+func readCloudTrailLakeResults(ctx context.Context, bucket, key string) (rows []cloudTrailLakeRow, err error) {
+	body, err := getS3Object(ctx, bucket, key)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	if cloudTrailLakeQueryFormat == cloudTrailLakeQueryFormatCsv {
+		return parseCloudTrailLakeCsv(body)
+	}
+	return parseCloudTrailLakeJson(body)
+}
+
+// JSON query results are an array of objects, one per selected row
+func parseCloudTrailLakeJson(reader io.Reader) (rows []cloudTrailLakeRow, err error) {
+	var items []json.RawMessage
+	if err = json.NewDecoder(reader).Decode(&items); err != nil {
+		return
+	}
+
+	for _, item := range items {
+		row := cloudTrailLakeRow{body: string(item)}
+		if err = json.Unmarshal(item, &row.fields); err != nil {
+			return
+		}
+		rows = append(rows, row)
+	}
+	return
+}
+
+// CSV query results start with a header row holding the selected column names.
+// The log body is the raw text of the row, recovered from the input offsets of the csv reader.
+func parseCloudTrailLakeCsv(reader io.Reader) (rows []cloudTrailLakeRow, err error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return
+	}
+	csvReader := csv.NewReader(bytes.NewReader(data))
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return
+	}
+
+	offset := csvReader.InputOffset()
+	for {
+		record, readErr := csvReader.Read()
+		if readErr == io.EOF {
+			return
+		} else if readErr != nil {
+			err = readErr
+			return
+		}
+		next := csvReader.InputOffset()
+		row := cloudTrailLakeRow{
+			fields: make(map[string]interface{}, len(header)),
+			body:   strings.Trim(string(data[offset:next]), "\r\n"),
+		}
+		offset = next
+		for i, column := range header {
+			if record[i] != "" {
+				row.fields[column] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// transformCloudTrailLakeRows groups the query result rows by the account which recorded the event
+func transformCloudTrailLakeRows(ctx context.Context, logGroup, logStream string, rows []cloudTrailLakeRow, output chan pdata.Logs) {
+	defer close(output)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			appLogger.Error("PANIC in transformCloudTrailLakeRows: ", recovered, "\n", string(debug.Stack()))
+		}
+	}()
+
+	accounts := make([]string, 0)
+	builders := make(map[string]OtlpRequestBuilder)
+	for _, row := range rows {
+		message, err := json.Marshal(row.fields)
+		if err != nil {
+			appLogger.Warn("While encoding CloudTrail Lake query result: ", err.Error())
+			continue
+		}
+		event := cloudTrailEvent{}
+		if err := json.Unmarshal(message, &event); err != nil {
+			appLogger.Warn("While decoding CloudTrail Lake query result: ", err.Error())
+			continue
+		}
+		eventId, _ := row.fields["eventID"].(string)
+		timestamp, ok := getCloudTrailLakeTimestamp(row.fields)
+		if !ok {
+			appLogger.Warn("Skipping CloudTrail Lake query result without a valid eventTime: ", eventId)
+			continue
+		}
+
+		account, _ := row.fields["recipientAccountId"].(string)
+		reqBuilder, exists := builders[account]
+		if !exists {
+			reqBuilder = NewOtlpRequestBuilder().
+				SetCloudAccount(account).
+				SetLogGroup(logGroup).
+				SetLogStream(logStream).
+				SetLambdaInvocationContext(ctx)
+			accounts = append(accounts, account)
+		} else if maxLogsPerBatch > 0 && reqBuilder.GetLogCount() >= maxLogsPerBatch {
+			output <- reqBuilder.GetLogs()
+			reqBuilder = reqBuilder.NextBatch()
+		}
+		builders[account] = reqBuilder
+
+		severity, severityText := getCloudTrailEventSeverity(&event)
+		reqBuilder.AddLogEntryWithSeverity(eventId, timestamp, row.body, event.getRegion(), severity, severityText, map[string]interface{}{
+			cloudTrailLakeEventIdAttribute: eventId,
+		})
+	}
+
+	for _, account := range accounts {
+		if reqBuilder := builders[account]; reqBuilder.HasLogEntries() {
+			output <- reqBuilder.GetLogs()
+		}
+	}
+}
+
+func getCloudTrailLakeTimestamp(row map[string]interface{}) (int64, bool) {
+	eventTime, _ := row["eventTime"].(string)
+	for _, layout := range cloudTrailLakeTimeLayouts {
+		if parsed, err := time.Parse(layout, eventTime); err == nil {
+			return parsed.UnixNano(), true
+		}
+	}
+	return 0, false
+}
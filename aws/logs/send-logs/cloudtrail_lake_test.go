@@ -0,0 +1,176 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestParseCloudTrailLakeResults(t *testing.T) {
+	for _, tc := range []struct {
+		file  string
+		parse func(io.Reader) ([]cloudTrailLakeRow, error)
+	}{
+		{"testdata/cloudtrail_lake_results.json", parseCloudTrailLakeJson},
+		{"testdata/cloudtrail_lake_results.csv", parseCloudTrailLakeCsv},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			file, err := os.Open(tc.file)
+			assert.Nil(t, err)
+			defer file.Close()
+
+			rows, err := tc.parse(file)
+			assert.Nil(t, err)
+			assert.Equal(t, 3, len(rows))
+			assert.Equal(t, "4f1e2d3c-5b6a-4978-8695-a4b3c2d1e0f9", rows[0].fields["eventID"])
+			assert.Equal(t, "GetObject", rows[0].fields["eventName"])
+			assert.Contains(t, rows[0].body, "4f1e2d3c-5b6a-4978-8695-a4b3c2d1e0f9")
+			assert.Equal(t, "AccessDenied", rows[1].fields["errorCode"])
+			assert.NotContains(t, rows[2].fields, "errorCode")
+		})
+	}
+}
+
+func TestParseCloudTrailLakeCsvQuotedValues(t *testing.T) {
+	results := "eventID,requestParameters\r\n" +
+		"1,\"{\"\"bucketName\"\": \"\"logs, archive\"\"}\"\r\n" +
+		"2,\"first line\nsecond line\"\r\n" +
+		"3,\"\"\"quoted\"\" line\r\n\"\"end\"\"\"\r\n"
+
+	rows, err := parseCloudTrailLakeCsv(strings.NewReader(results))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(rows))
+	assert.Equal(t, `{"bucketName": "logs, archive"}`, rows[0].fields["requestParameters"])
+	assert.Equal(t, `1,"{""bucketName"": ""logs, archive""}"`, rows[0].body)
+	assert.Equal(t, "first line\nsecond line", rows[1].fields["requestParameters"])
+	assert.Equal(t, "2,\"first line\nsecond line\"", rows[1].body)
+	assert.Equal(t, "\"quoted\" line\n\"end\"", rows[2].fields["requestParameters"])
+	assert.Equal(t, "3,\"\"\"quoted\"\" line\r\n\"\"end\"\"\"", rows[2].body)
+
+	_, err = parseCloudTrailLakeCsv(strings.NewReader("eventID,eventName\n1,GetObject,extra\n"))
+	assert.NotNil(t, err)
+}
+
+func TestTransformCloudTrailLakeRows(t *testing.T) {
+	file, err := os.Open("testdata/cloudtrail_lake_results.csv")
+	assert.Nil(t, err)
+	defer file.Close()
+	rows, err := parseCloudTrailLakeCsv(file)
+	assert.Nil(t, err)
+
+	output := make(chan pdata.Logs)
+	go transformCloudTrailLakeRows(context.Background(), "cloudtrail-lake", "results/2024-01-15.csv", rows, output)
+
+	logs := <-output
+	assertLogRecordHasAttribute(t, logs.ResourceLogs().At(0).Resource().Attributes(), "cloud.account.id", "123456789012")
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, 2, logRecords.Len())
+	assert.Equal(t, "4f1e2d3c-5b6a-4978-8695-a4b3c2d1e0f9,2024-01-15 10:30:45.000,1.08,s3.amazonaws.com,GetObject,us-east-1,,123456789012", logRecords.At(0).Body().StringVal())
+	assertLogRecordHasAttribute(t, logRecords.At(0).Attributes(), cloudTrailLakeEventIdAttribute, "4f1e2d3c-5b6a-4978-8695-a4b3c2d1e0f9")
+	assertLogRecordHasAttribute(t, logRecords.At(0).Attributes(), "cloud.region", "us-east-1")
+	assert.Equal(t, pdata.NewTimestampFromTime(time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)), logRecords.At(0).Timestamp())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, logRecords.At(0).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberERROR, logRecords.At(1).SeverityNumber())
+
+	logs = <-output
+	assertLogRecordHasAttribute(t, logs.ResourceLogs().At(0).Resource().Attributes(), "cloud.account.id", "210987654321")
+	assert.Equal(t, 1, logs.LogRecordCount())
+
+	_, open := <-output
+	assert.False(t, open)
+}
+
+func TestTransformCloudTrailLakeRowsSkipsUndecodableRows(t *testing.T) {
+	rows, err := parseCloudTrailLakeJson(strings.NewReader(`[
+		{"eventID": "1", "awsRegion": 1, "eventTime": "2024-01-15 10:30:45.000", "recipientAccountId": "123456789012"},
+		{"eventID": "2", "awsRegion": "us-east-1", "eventTime": "2024-01-15 10:30:46.000", "recipientAccountId": "123456789012"},
+		{"eventID": "3", "awsRegion": "us-east-1", "recipientAccountId": "123456789012"},
+		{"eventID": "4", "awsRegion": "us-east-1", "eventTime": "yesterday", "recipientAccountId": "123456789012"}
+	]`))
+	assert.Nil(t, err)
+
+	output := make(chan pdata.Logs)
+	go transformCloudTrailLakeRows(context.Background(), "cloudtrail-lake", "results/2024-01-15.json", rows, output)
+
+	logs := <-output
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, 1, logRecords.Len())
+	assert.Equal(t, `{"eventID": "2", "awsRegion": "us-east-1", "eventTime": "2024-01-15 10:30:46.000", "recipientAccountId": "123456789012"}`, logRecords.At(0).Body().StringVal())
+
+	_, open := <-output
+	assert.False(t, open)
+}
+
+func TestHandleCloudTrailLakeEvent(t *testing.T) {
+	server, serverEndpoint := startMockLogsServer(t)
+
+	originalEndpoint, originalGetS3Object, originalQueryFormat := endpoint, getS3Object, cloudTrailLakeQueryFormat
+	endpoint = serverEndpoint
+	getS3Object = func(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+		return os.Open("testdata/" + key)
+	}
+	cloudTrailLakeQueryFormat = cloudTrailLakeQueryFormatJson
+	t.Cleanup(func() {
+		endpoint, getS3Object, cloudTrailLakeQueryFormat = originalEndpoint, originalGetS3Object, originalQueryFormat
+	})
+
+	event := events.S3Event{Records: []events.S3EventRecord{{
+		S3: events.S3Entity{
+			Bucket: events.S3Bucket{Name: "query-results"},
+			Object: events.S3Object{URLDecodedKey: "cloudtrail_lake_results.json"},
+		},
+	}}}
+	result, err := HandleCloudTrailLakeEvent(context.Background(), event)
+	assert.Nil(t, err)
+	assert.Equal(t, "success", result)
+	assert.ElementsMatch(t, []string{"us-east-1", "us-east-1", "eu-west-1"}, server.received)
+
+	event.Records[0].S3.Object.URLDecodedKey = "missing.json"
+	result, err = HandleCloudTrailLakeEvent(context.Background(), event)
+	assert.NotNil(t, err)
+	assert.Equal(t, "failure", result)
+}
+
+func TestHandleCloudTrailLakeEvent_InvalidQueryFormat(t *testing.T) {
+	originalQueryFormat, originalGetS3Object := cloudTrailLakeQueryFormat, getS3Object
+	cloudTrailLakeQueryFormat = "parquet"
+	getS3Object = func(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+		t.Fatal("query results are read despite the invalid format")
+		return nil, nil
+	}
+	t.Cleanup(func() {
+		cloudTrailLakeQueryFormat, getS3Object = originalQueryFormat, originalGetS3Object
+	})
+
+	event := events.S3Event{Records: []events.S3EventRecord{{
+		S3: events.S3Entity{
+			Bucket: events.S3Bucket{Name: "query-results"},
+			Object: events.S3Object{URLDecodedKey: "cloudtrail_lake_results.json"},
+		},
+	}}}
+	result, err := HandleCloudTrailLakeEvent(context.Background(), event)
+	assert.ErrorContains(t, err, cloudTrailLakeQueryFormatVar)
+	assert.Equal(t, "failure", result)
+}
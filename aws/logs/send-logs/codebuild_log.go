@@ -0,0 +1,116 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"regexp"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	codeBuildLogGroupPatternVar = "CODEBUILD_LOG_GROUP_PATTERN"
+	codeBuildPhaseCompleted     = "COMPLETED"
+	codeBuildPhaseFailed        = "FAILED"
+)
+
+var (
+	codeBuildLogGroups           = newLogGroupFilter(codeBuildLogGroupPatternVar, "/aws/codebuild/.*")
+	detectCodeBuildProject       = regexp.MustCompile(`^/aws/codebuild/(?P<Project>[^/]+)`)
+	codeBuildProjectParamIndex   = detectCodeBuildProject.SubexpIndex("Project")
+	detectCodeBuildPhaseMarker   = regexp.MustCompile(`^\[Container\] [\d/]+ (?:[\d:.]+ )?Phase (?:is (?P<Phase>\w+)|complete: (?P<CompletedPhase>\w+) State: (?P<State>\w+))`)
+	codeBuildPhaseParamIndex     = detectCodeBuildPhaseMarker.SubexpIndex("Phase")
+	codeBuildCompletedParamIndex = detectCodeBuildPhaseMarker.SubexpIndex("CompletedPhase")
+	codeBuildStateParamIndex     = detectCodeBuildPhaseMarker.SubexpIndex("State")
+)
+
+// CodeBuild build log, the log group is named /aws/codebuild/<project-name> and the log stream after the build id
+type codeBuildLogSource struct {
+	projectName string
+	buildId     string
+}
+
+// CodeBuild phase marker, e.g. "[Container] 2024/01/15 10:30:45 Phase is COMPLETED"
+type codeBuildPhaseMarker struct {
+	Phase string
+	State string
+}
+
+func newCodeBuildLogSource(logGroup, logStream string) (source *codeBuildLogSource, ok bool) {
+	if !codeBuildLogGroups.match(logGroup) {
+		return
+	}
+
+	matches := detectCodeBuildProject.FindStringSubmatch(logGroup)
+	if codeBuildProjectParamIndex >= len(matches) || matches[codeBuildProjectParamIndex] == "" {
+		return
+	}
+
+	source = &codeBuildLogSource{
+		projectName: matches[codeBuildProjectParamIndex],
+		buildId:     logStream,
+	}
+	ok = true
+	return
+}
+
+func (src *codeBuildLogSource) setResourceAttributes(reqBuilder OtlpRequestBuilder) OtlpRequestBuilder {
+	return reqBuilder.
+		SetCicdPipelineName(src.projectName).
+		SetCicdPipelineRunId(src.buildId)
+}
+
+func (src *codeBuildLogSource) addLogEntry(reqBuilder OtlpRequestBuilder, itemId string, timestamp int64, message string) OtlpRequestBuilder {
+	marker, ok := parseCodeBuildPhaseMarker(message)
+	if !ok {
+		return reqBuilder.AddLogEntry(itemId, timestamp, message, lambdaRegion)
+	}
+
+	severity, severityText := marker.getSeverity()
+	return reqBuilder.AddLogEntryWithSeverity(itemId, timestamp, message, lambdaRegion, severity, severityText, map[string]interface{}{
+		"cicd.pipeline.task.name": marker.Phase,
+	})
+}
+
+func parseCodeBuildPhaseMarker(message string) (marker codeBuildPhaseMarker, ok bool) {
+	matches := detectCodeBuildPhaseMarker.FindStringSubmatch(message)
+	if matches == nil {
+		return
+	}
+
+	if matches[codeBuildPhaseParamIndex] != "" {
+		// "Phase is <PHASE>" marker reports the state of the whole build, e.g. COMPLETED or FAILED
+		marker.Phase = matches[codeBuildPhaseParamIndex]
+		marker.State = matches[codeBuildPhaseParamIndex]
+	} else {
+		marker.Phase = matches[codeBuildCompletedParamIndex]
+		marker.State = matches[codeBuildStateParamIndex]
+	}
+	ok = true
+	return
+}
+
+func (marker codeBuildPhaseMarker) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	switch marker.State {
+	case codeBuildPhaseFailed:
+		severity = pdata.SeverityNumberERROR
+		severityText = "ERROR"
+	case codeBuildPhaseCompleted, "SUCCEEDED":
+		severity = pdata.SeverityNumberINFO
+		severityText = "INFO"
+	}
+	return
+}
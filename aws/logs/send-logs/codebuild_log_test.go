@@ -0,0 +1,98 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestParseCodeBuildPhaseMarker(t *testing.T) {
+	testCases := []struct {
+		message  string
+		ok       bool
+		phase    string
+		severity pdata.SeverityNumber
+	}{
+		{"[Container] 2024/01/15 Phase is COMPLETED", true, "COMPLETED", pdata.SeverityNumberINFO},
+		{"[Container] 2024/01/15 10:30:52.001003 Phase is FAILED", true, "FAILED", pdata.SeverityNumberERROR},
+		{"[Container] 2024/01/15 10:30:41.351224 Phase is DOWNLOAD_SOURCE", true, "DOWNLOAD_SOURCE", pdata.SeverityNumberUNDEFINED},
+		{"[Container] 2024/01/15 10:30:51.876005 Phase complete: BUILD State: FAILED", true, "BUILD", pdata.SeverityNumberERROR},
+		{"[Container] 2024/01/15 10:30:42.102311 Phase complete: DOWNLOAD_SOURCE State: SUCCEEDED", true, "DOWNLOAD_SOURCE", pdata.SeverityNumberINFO},
+		{"[Container] 2024/01/15 10:30:45.223120 Running command go build ./...", false, "", pdata.SeverityNumberUNDEFINED},
+		{"Phase is COMPLETED", false, "", pdata.SeverityNumberUNDEFINED},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.message, func(t *testing.T) {
+			marker, ok := parseCodeBuildPhaseMarker(tc.message)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.phase, marker.Phase)
+			severity, _ := marker.getSeverity()
+			assert.Equal(t, tc.severity, severity)
+		})
+	}
+}
+
+func TestCodeBuildLogSourceDetection(t *testing.T) {
+	source, ok := newCodeBuildLogSource("/aws/codebuild/my-project", "8c6bd3a4-6f5e-4c39-9a55-3b8c2e1f0d11")
+	assert.True(t, ok)
+	assert.Equal(t, "my-project", source.projectName)
+	assert.Equal(t, "8c6bd3a4-6f5e-4c39-9a55-3b8c2e1f0d11", source.buildId)
+
+	_, ok = newCodeBuildLogSource("/aws/lambda/my-function", "2022/02/06/[$LATEST]abcd1234")
+	assert.False(t, ok)
+}
+
+func TestLogEventsTransformCodeBuildLog(t *testing.T) {
+	data, err := os.ReadFile("testdata/codebuild_log.txt")
+	assert.Nil(t, err)
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        "1",
+			Timestamp: time.Now().Unix(),
+			Message:   line,
+		})
+	}
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws/codebuild/my-project", "8c6bd3a4-6f5e-4c39-9a55-3b8c2e1f0d11", logEvents, output, nil, nil)
+	logs := <-output
+
+	resourceAttributes := logs.ResourceLogs().At(0).Resource().Attributes()
+	assertLogRecordHasAttribute(t, resourceAttributes, "cicd.pipeline.name", "my-project")
+	assertLogRecordHasAttribute(t, resourceAttributes, "cicd.pipeline.run.id", "8c6bd3a4-6f5e-4c39-9a55-3b8c2e1f0d11")
+
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, len(logEvents), logRecords.Len())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, logRecords.At(0).SeverityNumber())
+	assertLogRecordHasAttribute(t, logRecords.At(1).Attributes(), "cicd.pipeline.task.name", "DOWNLOAD_SOURCE")
+	assert.Equal(t, pdata.SeverityNumberINFO, logRecords.At(2).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberERROR, logRecords.At(4).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberINFO, logRecords.At(5).SeverityNumber())
+
+	for range output {
+	}
+}
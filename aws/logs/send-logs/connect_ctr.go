@@ -0,0 +1,115 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	connectLogGroupPatternVar       = "CONNECT_LOG_GROUP_PATTERN"
+	connectLongCallThresholdVar     = "CONNECT_LONG_CALL_THRESHOLD_SECONDS"
+	defaultConnectLongCallThreshold = 3600
+	connectCallDurationMetricName   = "aws.connect.call_duration_seconds"
+)
+
+var (
+	connectLogGroups         = newLogGroupFilter(connectLogGroupPatternVar, "/aws/connect/")
+	connectLongCallThreshold = getEnvInt(connectLongCallThresholdVar, defaultConnectLongCallThreshold)
+)
+
+type connectAgent struct {
+	Username string `json:"Username"`
+}
+
+type connectQueue struct {
+	Name string `json:"Name"`
+}
+
+type connectEndpoint struct {
+	Address string `json:"Address"`
+}
+
+// Amazon Connect contact trace record, Agent and Queue are null for contacts not routed to an agent
+type connectCTR struct {
+	ContactId           string           `json:"ContactId"`
+	Channel             string           `json:"Channel"`
+	InitiationTimestamp string           `json:"InitiationTimestamp"`
+	DisconnectTimestamp string           `json:"DisconnectTimestamp"`
+	Agent               *connectAgent    `json:"Agent"`
+	Queue               *connectQueue    `json:"Queue"`
+	SystemEndpoint      *connectEndpoint `json:"SystemEndpoint"`
+	Duration            int64            `json:"Duration"`
+}
+
+func isConnectCTR(logGroup string, jsonEvent map[string]interface{}) bool {
+	return connectLogGroups.match(logGroup) &&
+		testJsonPath(jsonEvent, "ContactId") &&
+		testJsonPath(jsonEvent, "Channel")
+}
+
+func (evt *connectCTR) getInstanceId() (result string, err error) {
+	err = errors.New("Connect contact trace record doesn't contain EC2 Instance ID")
+	return
+}
+
+func (evt *connectCTR) getRegion() (result string) {
+	result = lambdaRegion
+	return
+}
+
+func (evt *connectCTR) getEventType() (result string) {
+	result = ec2Event
+	return
+}
+
+func (evt *connectCTR) getBody() (result string) {
+	return
+}
+
+func (evt *connectCTR) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	if evt.Duration > connectLongCallThreshold {
+		severity = pdata.SeverityNumberWARN
+		severityText = "WARN"
+	}
+	return
+}
+
+func (evt *connectCTR) getAttributes() (result map[string]interface{}) {
+	result = map[string]interface{}{
+		"aws.connect.contact_id": evt.ContactId,
+		"aws.connect.channel":    evt.Channel,
+	}
+	if evt.Queue != nil && evt.Queue.Name != "" {
+		result["aws.connect.queue"] = evt.Queue.Name
+	}
+	if evt.Agent != nil && evt.Agent.Username != "" {
+		result["enduser.id"] = evt.Agent.Username
+	}
+	return
+}
+
+func (evt *connectCTR) addMetrics(metricsBuilder OtlpMetricsBuilder, timestamp int64) {
+	attributes := map[string]interface{}{
+		"aws.connect.channel": evt.Channel,
+	}
+	if evt.Queue != nil && evt.Queue.Name != "" {
+		attributes["aws.connect.queue"] = evt.Queue.Name
+	}
+	metricsBuilder.AddGauge(connectCallDurationMetricName, "s", timestamp, float64(evt.Duration), attributes)
+}
@@ -0,0 +1,109 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestConnectCTRSeverity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		duration int64
+		severity pdata.SeverityNumber
+	}{
+		{"Short call", 120, pdata.SeverityNumberUNDEFINED},
+		{"Exactly threshold", defaultConnectLongCallThreshold, pdata.SeverityNumberUNDEFINED},
+		{"Long call", defaultConnectLongCallThreshold + 1, pdata.SeverityNumberWARN},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctr := connectCTR{Duration: tc.duration}
+			severity, _ := ctr.getSeverity()
+			assert.Equal(t, tc.severity, severity)
+		})
+	}
+}
+
+func TestConnectCTRDetection(t *testing.T) {
+	message, err := os.ReadFile("testdata/connect_ctr.json")
+	assert.Nil(t, err)
+
+	ok, result := parseMessage("/aws/connect/my-instance", string(message))
+	assert.True(t, ok)
+	assert.IsType(t, &connectCTR{}, result)
+
+	ok, _ = parseMessage("/aws/lambda/my-function", string(message))
+	assert.False(t, ok)
+}
+
+func TestLogEventsTransformConnectCTR(t *testing.T) {
+	message, err := os.ReadFile("testdata/connect_ctr.json")
+	assert.Nil(t, err)
+	chatMessage := strings.NewReplacer(
+		`"VOICE"`, `"CHAT"`,
+		`"Agent": {`, `"Agent": null, "UnusedAgent": {`,
+	).Replace(string(message))
+
+	logEvents := []events.CloudwatchLogsLogEvent{
+		{
+			ID:        "1",
+			Timestamp: time.Now().UnixMilli(),
+			Message:   string(message),
+		},
+		{
+			ID:        "2",
+			Timestamp: time.Now().UnixMilli(),
+			Message:   chatMessage,
+		},
+	}
+
+	output := make(chan pdata.Logs)
+	metricsOutput := make(chan pdata.Metrics)
+	go transformLogEvents(context.Background(), "test account", "/aws/connect/my-instance", "ctr", logEvents, output, metricsOutput, nil)
+
+	logs := <-output
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, 2, logRecords.Len())
+	attributes := logRecords.At(0).Attributes()
+	assertLogRecordHasAttribute(t, attributes, "aws.connect.contact_id", "5ca32fbd-8f92-46af-92a3-6b1f6c2c8a3e")
+	assertLogRecordHasAttribute(t, attributes, "aws.connect.channel", "VOICE")
+	assertLogRecordHasAttribute(t, attributes, "aws.connect.queue", "BasicQueue")
+	assertLogRecordHasAttribute(t, attributes, "enduser.id", "jdoe")
+	assertLogRecordHasAttribute(t, logRecords.At(1).Attributes(), "aws.connect.channel", "CHAT")
+	assertLogRecordDoNotHaveAttribute(t, logRecords.At(1).Attributes(), "enduser.id")
+	for range output {
+	}
+
+	metrics := <-metricsOutput
+	assert.Equal(t, 2, metrics.MetricCount())
+	metric := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, connectCallDurationMetricName, metric.Name())
+	assert.Equal(t, float64(678), metric.Gauge().DataPoints().At(0).DoubleVal())
+	assertLogRecordHasAttribute(t, metric.Gauge().DataPoints().At(0).Attributes(), "aws.connect.queue", "BasicQueue")
+
+	_, open := <-metricsOutput
+	assert.False(t, open)
+}
@@ -0,0 +1,89 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	dynamoDBLogGroupPatternVar         = "DYNAMODB_LOG_GROUP_PATTERN"
+	dynamoDBOperationRemove            = "REMOVE"
+	dynamoDBStreamOperationsMetricName = "aws.dynamodb.stream.operations"
+)
+
+var dynamoDBLogGroups = newLogGroupFilter(dynamoDBLogGroupPatternVar, "/aws/dynamodb/")
+
+// DynamoDB Streams operation log record
+type dynamoDBStreamLog struct {
+	TableName     string `json:"tableName"`
+	OperationType string `json:"operationType"`
+	Region        string `json:"awsRegion"`
+}
+
+func isDynamoDBStreamLog(logGroup string, jsonEvent map[string]interface{}) bool {
+	return dynamoDBLogGroups.match(logGroup) &&
+		testJsonPath(jsonEvent, "dynamodb.Keys") &&
+		testJsonPath(jsonEvent, "operationType")
+}
+
+func (evt *dynamoDBStreamLog) getInstanceId() (result string, err error) {
+	err = errors.New("DynamoDB stream log doesn't contain EC2 Instance ID")
+	return
+}
+
+func (evt *dynamoDBStreamLog) getRegion() (result string) {
+	result = evt.Region
+	if result == "" {
+		result = lambdaRegion
+	}
+	return
+}
+
+func (evt *dynamoDBStreamLog) getEventType() (result string) {
+	result = ec2Event
+	return
+}
+
+func (evt *dynamoDBStreamLog) getBody() (result string) {
+	return
+}
+
+func (evt *dynamoDBStreamLog) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	if evt.OperationType == dynamoDBOperationRemove {
+		severity = pdata.SeverityNumberWARN
+		severityText = "WARN"
+	}
+	return
+}
+
+func (evt *dynamoDBStreamLog) getAttributes() (result map[string]interface{}) {
+	result = map[string]interface{}{
+		"db.system":               "dynamodb",
+		"db.operation":            evt.OperationType,
+		"aws.dynamodb.table_name": evt.TableName,
+	}
+	return
+}
+
+func (evt *dynamoDBStreamLog) addMetrics(metricsBuilder OtlpMetricsBuilder, timestamp int64) {
+	metricsBuilder.AddCounter(dynamoDBStreamOperationsMetricName, "{operations}", timestamp, 1, map[string]interface{}{
+		"db.operation":            evt.OperationType,
+		"aws.dynamodb.table_name": evt.TableName,
+	})
+}
@@ -0,0 +1,92 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+)
+
+func TestDynamoDBStreamLogDetection(t *testing.T) {
+	message, err := os.ReadFile("testdata/dynamodb_stream.json")
+	assert.Nil(t, err)
+
+	ok, result := parseMessage("/aws/dynamodb/orders", string(message))
+	assert.True(t, ok)
+	assert.IsType(t, &dynamoDBStreamLog{}, result)
+
+	ok, _ = parseMessage("/aws/lambda/my-function", string(message))
+	assert.False(t, ok)
+}
+
+func TestLogEventsTransformDynamoDBStreamLog(t *testing.T) {
+	message, err := os.ReadFile("testdata/dynamodb_stream.json")
+	assert.Nil(t, err)
+	removeMessage := string(message)
+	insertMessage := strings.Replace(removeMessage, `"REMOVE"`, `"INSERT"`, 1)
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for i, message := range []string{insertMessage, removeMessage, insertMessage} {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        strconv.Itoa(i),
+			Timestamp: time.Now().UnixMilli(),
+			Message:   message,
+		})
+	}
+
+	output := make(chan pdata.Logs)
+	metricsOutput := make(chan pdata.Metrics)
+	go transformLogEvents(context.Background(), "test account", "/aws/dynamodb/orders", "stream", logEvents, output, metricsOutput, nil)
+
+	logs := <-output
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, 3, logRecords.Len())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, logRecords.At(0).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberWARN, logRecords.At(1).SeverityNumber())
+	attributes := logRecords.At(1).Attributes()
+	assertLogRecordHasAttribute(t, attributes, "db.system", "dynamodb")
+	assertLogRecordHasAttribute(t, attributes, "db.operation", "REMOVE")
+	assertLogRecordHasAttribute(t, attributes, "aws.dynamodb.table_name", "Orders")
+	assertLogRecordHasAttribute(t, attributes, semconv.AttributeCloudRegion, "us-east-1")
+	for range output {
+	}
+
+	metrics := <-metricsOutput
+	assert.Equal(t, 2, metrics.MetricCount())
+	counts := map[string]int64{}
+	metricSlice := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metricSlice.Len(); i++ {
+		metric := metricSlice.At(i)
+		assert.Equal(t, dynamoDBStreamOperationsMetricName, metric.Name())
+		assert.Equal(t, pdata.MetricDataTypeSum, metric.DataType())
+		dataPoint := metric.Sum().DataPoints().At(0)
+		operation, _ := dataPoint.Attributes().Get("db.operation")
+		counts[operation.StringVal()] = dataPoint.IntVal()
+	}
+	assert.Equal(t, map[string]int64{"INSERT": 2, "REMOVE": 1}, counts)
+
+	for range metricsOutput {
+	}
+}
@@ -0,0 +1,105 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	elastiCacheLogGroupPatternVar    = "ELASTICACHE_LOG_GROUP_PATTERN"
+	redisSlowLogThresholdVar         = "REDIS_SLOW_LOG_THRESHOLD_US"
+	defaultRedisSlowLogThreshold     = 100000
+	redisSlowQueryDurationMetricName = "db.redis.slow_query.duration"
+)
+
+var (
+	elastiCacheLogGroups  = newLogGroupFilter(elastiCacheLogGroupPatternVar, "/aws/elasticache/")
+	redisSlowLogThreshold = getEnvInt(redisSlowLogThresholdVar, defaultRedisSlowLogThreshold)
+)
+
+// ElastiCache for Redis slow log entry
+type elastiCacheSlowLog struct {
+	DurationMicroseconds int64    `json:"duration_microseconds"`
+	Command              []string `json:"command"`
+	ClientAddress        string   `json:"client_address"`
+	NodeId               string   `json:"node_id"`
+}
+
+func isElastiCacheSlowLog(logGroup string, jsonEvent map[string]interface{}) bool {
+	return elastiCacheLogGroups.match(logGroup) &&
+		testJsonPath(jsonEvent, "duration_microseconds") &&
+		testJsonPath(jsonEvent, "node_id")
+}
+
+func (evt *elastiCacheSlowLog) getInstanceId() (result string, err error) {
+	err = errors.New("ElastiCache slow log doesn't contain EC2 Instance ID")
+	return
+}
+
+func (evt *elastiCacheSlowLog) getRegion() (result string) {
+	result = lambdaRegion
+	return
+}
+
+func (evt *elastiCacheSlowLog) getEventType() (result string) {
+	result = ec2Event
+	return
+}
+
+func (evt *elastiCacheSlowLog) getBody() (result string) {
+	return
+}
+
+func (evt *elastiCacheSlowLog) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	if evt.DurationMicroseconds > redisSlowLogThreshold {
+		severity = pdata.SeverityNumberWARN
+		severityText = "WARN"
+	}
+	return
+}
+
+func (evt *elastiCacheSlowLog) getAttributes() (result map[string]interface{}) {
+	result = map[string]interface{}{
+		"db.system":        "redis",
+		"db.statement":     strings.Join(evt.Command, " "),
+		"db.redis.node_id": evt.NodeId,
+	}
+	if peerIp := evt.getClientIp(); peerIp != "" {
+		result["net.peer.ip"] = peerIp
+	}
+	return
+}
+
+func (evt *elastiCacheSlowLog) addMetrics(metricsBuilder OtlpMetricsBuilder, timestamp int64) {
+	metricsBuilder.AddGauge(redisSlowQueryDurationMetricName, "us", timestamp, float64(evt.DurationMicroseconds), map[string]interface{}{
+		"db.system":        "redis",
+		"db.redis.node_id": evt.NodeId,
+	})
+}
+
+// client address is reported as "ip:port"
+func (evt *elastiCacheSlowLog) getClientIp() string {
+	host, _, err := net.SplitHostPort(evt.ClientAddress)
+	if err != nil {
+		return evt.ClientAddress
+	}
+	return host
+}
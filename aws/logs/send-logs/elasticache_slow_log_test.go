@@ -0,0 +1,86 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestElastiCacheSlowLogSeverity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		duration int64
+		severity pdata.SeverityNumber
+	}{
+		{"Below threshold", defaultRedisSlowLogThreshold - 1, pdata.SeverityNumberUNDEFINED},
+		{"Exactly threshold", defaultRedisSlowLogThreshold, pdata.SeverityNumberUNDEFINED},
+		{"Above threshold", defaultRedisSlowLogThreshold + 1, pdata.SeverityNumberWARN},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			slowLog := elastiCacheSlowLog{DurationMicroseconds: tc.duration}
+			severity, _ := slowLog.getSeverity()
+			assert.Equal(t, tc.severity, severity)
+		})
+	}
+}
+
+func TestLogEventsTransformElastiCacheSlowLog(t *testing.T) {
+	message, err := os.ReadFile("testdata/elasticache_slow_log.json")
+	assert.Nil(t, err)
+
+	logEvents := []events.CloudwatchLogsLogEvent{
+		{
+			ID:        "1",
+			Timestamp: time.Now().UnixMilli(),
+			Message:   string(message),
+		},
+	}
+
+	output := make(chan pdata.Logs)
+	metricsOutput := make(chan pdata.Metrics)
+	go transformLogEvents(context.Background(), "test account", "/aws/elasticache/my-redis-cluster", "slow-log", logEvents, output, metricsOutput, nil)
+
+	logs := <-output
+	logRecord := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	attributes := logRecord.Attributes()
+	assert.Equal(t, pdata.SeverityNumberWARN, logRecord.SeverityNumber())
+	assertLogRecordHasAttribute(t, attributes, "db.system", "redis")
+	assertLogRecordHasAttribute(t, attributes, "db.statement", "KEYS session:*")
+	assertLogRecordHasAttribute(t, attributes, "db.redis.node_id", "my-redis-cluster-0001-001")
+	assertLogRecordHasAttribute(t, attributes, "net.peer.ip", "10.0.1.25")
+	for range output {
+	}
+
+	metrics := <-metricsOutput
+	assert.Equal(t, 1, metrics.MetricCount())
+	metric := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, redisSlowQueryDurationMetricName, metric.Name())
+	assert.Equal(t, pdata.MetricDataTypeGauge, metric.DataType())
+	assert.Equal(t, float64(250000), metric.Gauge().DataPoints().At(0).DoubleVal())
+	assertLogRecordHasAttribute(t, metric.Gauge().DataPoints().At(0).Attributes(), "db.redis.node_id", "my-redis-cluster-0001-001")
+
+	_, open := <-metricsOutput
+	assert.False(t, open)
+}
@@ -0,0 +1,100 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-lambda-go/events"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const otlpEndpointMapVar = "OTLP_ENDPOINT_MAP"
+
+var (
+	endpointMap = parseEndpointMap(os.Getenv(otlpEndpointMapVar))
+	// otlp/gRPC endpoint -> *grpc.ClientConn. The connections are reused across invocations and live as long as
+	// the Lambda execution environment, they are only closed by closeClientConns.
+	clientConns sync.Map
+)
+
+// parse region to otlp/gRPC endpoint mapping, e.g. {"us-east-1":"endpoint1:4317","eu-west-1":"endpoint2:4317"}
+func parseEndpointMap(value string) map[string]string {
+	if value == "" {
+		return nil
+	}
+
+	result := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		appLogger.Error("Invalid ", otlpEndpointMapVar, " value, using ", otlpEndpointVar, " for all regions: ", err.Error())
+		return nil
+	}
+	return result
+}
+
+// otlp/gRPC endpoint for the region of the first log event, OTLP_ENDPOINT is used for regions without a mapping
+func regionEndpoint(logGroup string, logEvents []events.CloudwatchLogsLogEvent) string {
+	if len(endpointMap) == 0 || len(logEvents) == 0 {
+		return endpoint
+	}
+
+	region := lambdaRegion
+	if ok, event := parseMessage(logGroup, logEvents[0].Message); ok && event.getRegion() != "" {
+		region = event.getRegion()
+	}
+
+	if regionEndpoint, exists := endpointMap[region]; exists {
+		return regionEndpoint
+	}
+	return endpoint
+}
+
+func getClientConn(target string) (*grpc.ClientConn, error) {
+	if conn, exists := clientConns.Load(target); exists {
+		return conn.(*grpc.ClientConn), nil
+	}
+
+	dialOption := grpc.WithInsecure()
+
+	if executingInAWS {
+		config := &tls.Config{}
+		dialOption = grpc.WithTransportCredentials(credentials.NewTLS(config))
+	}
+
+	conn, err := grpc.Dial(target, dialOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, loaded := clientConns.LoadOrStore(target, conn); loaded {
+		conn.Close()
+		return existing.(*grpc.ClientConn), nil
+	}
+	return conn, nil
+}
+
+// closeClientConns closes and forgets the cached connections, the next getClientConn call dials again
+func closeClientConns() {
+	clientConns.Range(func(target, conn interface{}) bool {
+		clientConns.Delete(target)
+		conn.(*grpc.ClientConn).Close()
+		return true
+	})
+}
@@ -0,0 +1,148 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/otlpgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+type mockLogsServer struct {
+	mutex    sync.Mutex
+	received []string // aws region of the received log records
+}
+
+func (s *mockLogsServer) Export(ctx context.Context, request otlpgrpc.LogsRequest) (otlpgrpc.LogsResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	resourceLogs := request.Logs().ResourceLogs()
+	for i := 0; i < resourceLogs.Len(); i++ {
+		logs := resourceLogs.At(i).InstrumentationLibraryLogs().At(0).Logs()
+		for j := 0; j < logs.Len(); j++ {
+			var event cloudTrailEvent
+			json.Unmarshal([]byte(logs.At(j).Body().StringVal()), &event)
+			s.received = append(s.received, event.Region)
+		}
+	}
+	return otlpgrpc.NewLogsResponse(), nil
+}
+
+func startMockLogsServer(t *testing.T) (*mockLogsServer, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	mock := &mockLogsServer{}
+	otlpgrpc.RegisterLogsServer(server, mock)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	t.Cleanup(closeClientConns)
+
+	return mock, listener.Addr().String()
+}
+
+func newCloudwatchLogsEvent(t *testing.T, logGroup string, messages ...string) events.CloudwatchLogsEvent {
+	data := events.CloudwatchLogsData{
+		Owner:     "123456789012",
+		LogGroup:  logGroup,
+		LogStream: "stream",
+	}
+	for i, message := range messages {
+		data.LogEvents = append(data.LogEvents, events.CloudwatchLogsLogEvent{
+			ID:        string(rune('a' + i)),
+			Timestamp: 1620000000000,
+			Message:   message,
+		})
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write(payload)
+	writer.Close()
+
+	return events.CloudwatchLogsEvent{
+		AWSLogs: events.CloudwatchLogsRawData{Data: base64.StdEncoding.EncodeToString(compressed.Bytes())},
+	}
+}
+
+func TestParseEndpointMap(t *testing.T) {
+	assert.Nil(t, parseEndpointMap(""))
+	assert.Nil(t, parseEndpointMap("{invalid"))
+	assert.Equal(t, map[string]string{"us-east-1": "endpoint1:4317"}, parseEndpointMap(`{"us-east-1":"endpoint1:4317"}`))
+}
+
+func TestHandleEvent_RegionSpecificEndpoint(t *testing.T) {
+	usServer, usEndpoint := startMockLogsServer(t)
+	euServer, euEndpoint := startMockLogsServer(t)
+	defaultServer, defaultEndpoint := startMockLogsServer(t)
+
+	originalEndpoint, originalEndpointMap := endpoint, endpointMap
+	endpoint = defaultEndpoint
+	endpointMap = map[string]string{
+		"us-east-1": usEndpoint,
+		"eu-west-1": euEndpoint,
+	}
+	t.Cleanup(func() {
+		endpoint, endpointMap = originalEndpoint, originalEndpointMap
+	})
+
+	for _, region := range []string{"us-east-1", "eu-west-1", "ap-south-1"} {
+		message := `{"eventVersion":"1.08","eventSource":"s3.amazonaws.com","eventName":"GetObject","awsRegion":"` + region + `"}`
+		result, err := handleEvent(context.Background(), newCloudwatchLogsEvent(t, "/aws/cloudtrail", message))
+		assert.Nil(t, err)
+		assert.Equal(t, "success", result)
+	}
+
+	assert.Equal(t, []string{"us-east-1"}, usServer.received)
+	assert.Equal(t, []string{"eu-west-1"}, euServer.received)
+	assert.Equal(t, []string{"ap-south-1"}, defaultServer.received)
+}
+
+func TestCloseClientConns(t *testing.T) {
+	_, serverEndpoint := startMockLogsServer(t)
+
+	conn, err := getClientConn(serverEndpoint)
+	assert.Nil(t, err)
+	cached, err := getClientConn(serverEndpoint)
+	assert.Nil(t, err)
+	assert.Same(t, conn, cached)
+
+	closeClientConns()
+	assert.Equal(t, connectivity.Shutdown, conn.GetState())
+
+	redialed, err := getClientConn(serverEndpoint)
+	assert.Nil(t, err)
+	assert.NotSame(t, conn, redialed)
+}
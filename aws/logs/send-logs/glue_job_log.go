@@ -0,0 +1,83 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"os"
+	"regexp"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	glueLogGroupPatternVar = "GLUE_LOG_GROUP_PATTERN"
+	glueJobNameVar         = "GLUE_JOB_NAME"
+)
+
+var (
+	glueLogGroups       = newLogGroupFilter(glueLogGroupPatternVar, "/aws-glue/jobs/.*")
+	glueJobName         = os.Getenv(glueJobNameVar)
+	detectGlueLog4jLine = regexp.MustCompile(`^[\d/:\-.,]+ [\d:.,]+ (?P<Level>ERROR|WARN|INFO) `)
+	glueLevelIndex      = detectGlueLog4jLine.SubexpIndex("Level")
+)
+
+// Glue ETL job log, the output and error log groups hold one log stream per job run
+type glueJobLog struct {
+	jobRunId string
+}
+
+func newGlueLogSource(logGroup, logStream string) (source *glueJobLog, ok bool) {
+	if !glueLogGroups.match(logGroup) || logStream == "" {
+		return
+	}
+
+	source = &glueJobLog{
+		jobRunId: logStream,
+	}
+	ok = true
+	return
+}
+
+func (src *glueJobLog) setResourceAttributes(reqBuilder OtlpRequestBuilder) OtlpRequestBuilder {
+	if glueJobName != "" {
+		reqBuilder.SetGlueJobName(glueJobName)
+	}
+	return reqBuilder.SetGlueJobRunId(src.jobRunId)
+}
+
+func (src *glueJobLog) addLogEntry(reqBuilder OtlpRequestBuilder, itemId string, timestamp int64, message string) OtlpRequestBuilder {
+	severity, severityText := getGlueLogSeverity(message)
+	return reqBuilder.AddLogEntryWithSeverity(itemId, timestamp, message, lambdaRegion, severity, severityText)
+}
+
+// Severity of log4j formatted lines, e.g. "24/01/15 10:30:45 ERROR GlueContext: ...", PySpark and Scala output is left as is
+func getGlueLogSeverity(message string) (severity pdata.SeverityNumber, severityText string) {
+	matches := detectGlueLog4jLine.FindStringSubmatch(message)
+	if matches == nil {
+		return
+	}
+
+	switch matches[glueLevelIndex] {
+	case "ERROR":
+		severity = pdata.SeverityNumberERROR
+	case "WARN":
+		severity = pdata.SeverityNumberWARN
+	case "INFO":
+		severity = pdata.SeverityNumberINFO
+	}
+	severityText = matches[glueLevelIndex]
+	return
+}
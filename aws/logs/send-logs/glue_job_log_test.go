@@ -0,0 +1,86 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestGlueJobLogDetection(t *testing.T) {
+	testCases := []struct {
+		logGroup  string
+		logStream string
+		ok        bool
+	}{
+		{"/aws-glue/jobs/output", "jr_4f1e2d3c5b6a79808f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a29180f7e6d5c", true},
+		{"/aws-glue/jobs/error", "jr_4f1e2d3c5b6a79808f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a29180f7e6d5c", true},
+		{"/aws-glue/crawlers", "my-crawler", false},
+		{"/aws/lambda/my-function", "jr_4f1e2d3c5b6a79808f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a29180f7e6d5c", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.logGroup, func(t *testing.T) {
+			source, ok := newGlueLogSource(tc.logGroup, tc.logStream)
+			assert.Equal(t, tc.ok, ok)
+			if ok {
+				assert.Equal(t, tc.logStream, source.jobRunId)
+			}
+		})
+	}
+}
+
+func TestLogEventsTransformGlueJobLog(t *testing.T) {
+	data, err := os.ReadFile("testdata/glue_job.txt")
+	assert.Nil(t, err)
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        "1",
+			Timestamp: time.Now().Unix(),
+			Message:   line,
+		})
+	}
+
+	glueJobName = "nightly-etl"
+	defer func() { glueJobName = "" }()
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws-glue/jobs/output", "jr_4f1e2d3c5b6a7980", logEvents, output, nil, nil)
+	logs := <-output
+
+	resourceAttributes := logs.ResourceLogs().At(0).Resource().Attributes()
+	assertLogRecordHasAttribute(t, resourceAttributes, "aws.glue.job_name", "nightly-etl")
+	assertLogRecordHasAttribute(t, resourceAttributes, "aws.glue.job_run_id", "jr_4f1e2d3c5b6a7980")
+
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, len(logEvents), logRecords.Len())
+	assert.Equal(t, pdata.SeverityNumberINFO, logRecords.At(0).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberWARN, logRecords.At(1).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberERROR, logRecords.At(2).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, logRecords.At(3).SeverityNumber())
+
+	for range output {
+	}
+}
@@ -0,0 +1,103 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	iotLogGroupPatternVar = "IOT_LOG_GROUP_PATTERN"
+	iotStatusFailure      = "Failure"
+)
+
+// IoT Core writes its logs to the AWSIotLogsV2 log group (AWSIotLogs for legacy logging)
+var iotLogGroups = newLogGroupFilter(iotLogGroupPatternVar, "^AWSIotLogs")
+
+// AWS IoT Core message broker log entry
+type iotCoreLog struct {
+	LogLevel    string `json:"logLevel"`
+	TraceId     string `json:"traceId"`
+	AccountId   string `json:"accountId"`
+	Status      string `json:"status"`
+	EventType   string `json:"eventType"`
+	ClientId    string `json:"clientId"`
+	PrincipalId string `json:"principalId"`
+	Protocol    string `json:"protocol"`
+}
+
+func isIotCoreLog(logGroup string, jsonEvent map[string]interface{}) bool {
+	return iotLogGroups.match(logGroup) &&
+		testJsonPath(jsonEvent, "clientId") &&
+		testJsonPath(jsonEvent, "eventType")
+}
+
+func (evt *iotCoreLog) getInstanceId() (result string, err error) {
+	err = errors.New("IoT Core log doesn't contain EC2 Instance ID")
+	return
+}
+
+func (evt *iotCoreLog) getRegion() (result string) {
+	result = lambdaRegion
+	return
+}
+
+func (evt *iotCoreLog) getEventType() (result string) {
+	result = ec2Event
+	return
+}
+
+func (evt *iotCoreLog) getBody() (result string) {
+	return
+}
+
+// failed operations are reported as errors regardless of the log level
+func (evt *iotCoreLog) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	if evt.Status == iotStatusFailure {
+		severity = pdata.SeverityNumberERROR
+		severityText = "ERROR"
+		return
+	}
+
+	switch evt.LogLevel {
+	case "ERROR":
+		severity = pdata.SeverityNumberERROR
+	case "WARN":
+		severity = pdata.SeverityNumberWARN
+	case "INFO":
+		severity = pdata.SeverityNumberINFO
+	case "DEBUG":
+		severity = pdata.SeverityNumberDEBUG
+	default:
+		return
+	}
+	severityText = evt.LogLevel
+	return
+}
+
+func (evt *iotCoreLog) getAttributes() (result map[string]interface{}) {
+	result = map[string]interface{}{
+		"messaging.system":   "aws.iot",
+		"aws.iot.client_id":  evt.ClientId,
+		"aws.iot.event_type": evt.EventType,
+	}
+	if evt.Protocol != "" {
+		result["aws.iot.protocol"] = evt.Protocol
+	}
+	return
+}
@@ -0,0 +1,110 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestIotCoreLogDetection(t *testing.T) {
+	message, err := os.ReadFile("testdata/iot_core_log.json")
+	assert.Nil(t, err)
+
+	ok, result := parseMessage("AWSIotLogsV2", string(message))
+	assert.True(t, ok)
+	assert.IsType(t, &iotCoreLog{}, result)
+
+	ok, _ = parseMessage("/aws/lambda/my-function", string(message))
+	assert.False(t, ok)
+}
+
+func TestLogEventsTransformIotCoreLog(t *testing.T) {
+	message, err := os.ReadFile("testdata/iot_core_log.json")
+	assert.Nil(t, err)
+	connectMessage := string(message)
+	disconnectMessage := strings.NewReplacer(
+		`"Connect"`, `"Disconnect"`,
+		`"protocol": "MQTT",`, ``,
+	).Replace(connectMessage)
+	failureMessage := strings.NewReplacer(
+		`"INFO"`, `"ERROR"`,
+		`"Success"`, `"Failure"`,
+	).Replace(connectMessage)
+	warnMessage := strings.Replace(connectMessage, `"INFO"`, `"WARN"`, 1)
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for i, message := range []string{connectMessage, disconnectMessage, failureMessage, warnMessage} {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        strconv.Itoa(i),
+			Timestamp: time.Now().UnixMilli(),
+			Message:   message,
+		})
+	}
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "AWSIotLogsV2", "stream", logEvents, output, nil, nil)
+
+	logs := <-output
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, 4, logRecords.Len())
+
+	t.Run("Connect", func(t *testing.T) {
+		assert.Equal(t, pdata.SeverityNumberINFO, logRecords.At(0).SeverityNumber())
+		attributes := logRecords.At(0).Attributes()
+		assertLogRecordHasAttribute(t, attributes, "messaging.system", "aws.iot")
+		assertLogRecordHasAttribute(t, attributes, "aws.iot.client_id", "thermostat-42")
+		assertLogRecordHasAttribute(t, attributes, "aws.iot.event_type", "Connect")
+		assertLogRecordHasAttribute(t, attributes, "aws.iot.protocol", "MQTT")
+	})
+
+	t.Run("Disconnect", func(t *testing.T) {
+		attributes := logRecords.At(1).Attributes()
+		assertLogRecordHasAttribute(t, attributes, "aws.iot.event_type", "Disconnect")
+		assertLogRecordDoNotHaveAttribute(t, attributes, "aws.iot.protocol")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		assert.Equal(t, pdata.SeverityNumberERROR, logRecords.At(2).SeverityNumber())
+		assert.Equal(t, "ERROR", logRecords.At(2).SeverityText())
+	})
+
+	t.Run("Warning", func(t *testing.T) {
+		assert.Equal(t, pdata.SeverityNumberWARN, logRecords.At(3).SeverityNumber())
+	})
+
+	for range output {
+	}
+}
+
+func TestIotCoreLogFailureSeverity(t *testing.T) {
+	iotLog := iotCoreLog{LogLevel: "INFO", Status: iotStatusFailure}
+	severity, severityText := iotLog.getSeverity()
+	assert.Equal(t, pdata.SeverityNumberERROR, severity)
+	assert.Equal(t, "ERROR", severityText)
+
+	iotLog = iotCoreLog{LogLevel: "DISABLED", Status: "Success"}
+	severity, _ = iotLog.getSeverity()
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, severity)
+}
@@ -0,0 +1,93 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	parseLambdaReportVar           = "PARSE_LAMBDA_REPORT"
+	lambdaReportPrefix             = "REPORT RequestId:"
+	lambdaLogGroupPrefix           = "/aws/lambda/"
+	lambdaDurationMetricName       = "aws.lambda.duration_ms"
+	lambdaBilledDurationMetricName = "aws.lambda.billed_duration_ms"
+	lambdaMemoryUsedMetricName     = "aws.lambda.memory_used_mb"
+	lambdaInitDurationMetricName   = "aws.lambda.init_duration_ms"
+)
+
+var (
+	parseLambdaReport  = strings.EqualFold(os.Getenv(parseLambdaReportVar), "true")
+	lambdaReportRegExp = regexp.MustCompile(`^REPORT RequestId: (?P<RequestId>\S+)\s+Duration: (?P<Duration>[\d.]+) ms\s+Billed Duration: (?P<BilledDuration>\d+) ms\s+Memory Size: (?P<MemorySize>\d+) MB\s+Max Memory Used: (?P<MaxMemoryUsed>\d+) MB(?:\s+Init Duration: (?P<InitDuration>[\d.]+) ms)?`)
+)
+
+// Lambda REPORT line logged at the end of every invocation, Init Duration is only present for cold starts
+type lambdaReport struct {
+	RequestId      string
+	Duration       float64
+	BilledDuration float64
+	MemorySize     float64
+	MaxMemoryUsed  float64
+	InitDuration   float64
+	ColdStart      bool
+}
+
+func parseLambdaReportLine(message string) (report lambdaReport, ok bool) {
+	if !strings.HasPrefix(message, lambdaReportPrefix) {
+		return
+	}
+
+	matches := lambdaReportRegExp.FindStringSubmatch(message)
+	if matches == nil {
+		return
+	}
+
+	value := func(name string) float64 {
+		result, _ := strconv.ParseFloat(matches[lambdaReportRegExp.SubexpIndex(name)], 64)
+		return result
+	}
+
+	report = lambdaReport{
+		RequestId:      matches[lambdaReportRegExp.SubexpIndex("RequestId")],
+		Duration:       value("Duration"),
+		BilledDuration: value("BilledDuration"),
+		MemorySize:     value("MemorySize"),
+		MaxMemoryUsed:  value("MaxMemoryUsed"),
+	}
+	if initDuration := matches[lambdaReportRegExp.SubexpIndex("InitDuration")]; initDuration != "" {
+		report.InitDuration = value("InitDuration")
+		report.ColdStart = true
+	}
+	ok = true
+	return
+}
+
+func (r lambdaReport) addMetrics(metricsBuilder OtlpMetricsBuilder, logGroup string, timestamp int64) {
+	attributes := map[string]interface{}{
+		"faas.name": strings.TrimPrefix(logGroup, lambdaLogGroupPrefix),
+	}
+
+	metricsBuilder.
+		AddGauge(lambdaDurationMetricName, "ms", timestamp, r.Duration, attributes).
+		AddGauge(lambdaBilledDurationMetricName, "ms", timestamp, r.BilledDuration, attributes).
+		AddGauge(lambdaMemoryUsedMetricName, "MB", timestamp, r.MaxMemoryUsed, attributes)
+	if r.ColdStart {
+		metricsBuilder.AddGauge(lambdaInitDurationMetricName, "ms", timestamp, r.InitDuration, attributes)
+	}
+}
@@ -0,0 +1,121 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func readLambdaReportEvents(t *testing.T) []events.CloudwatchLogsLogEvent {
+	data, err := os.ReadFile("testdata/lambda_report.txt")
+	assert.Nil(t, err)
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        "1",
+			Timestamp: time.Now().UnixMilli(),
+			Message:   line,
+		})
+	}
+	return logEvents
+}
+
+func TestParseLambdaReportLine(t *testing.T) {
+	logEvents := readLambdaReportEvents(t)
+
+	_, ok := parseLambdaReportLine(logEvents[0].Message)
+	assert.False(t, ok)
+
+	report, ok := parseLambdaReportLine(logEvents[1].Message)
+	assert.True(t, ok)
+	assert.Equal(t, lambdaReport{
+		RequestId:      "8f507cfc-4b9a-4a8c-9e3a-1f2b3c4d5e6f",
+		Duration:       102.25,
+		BilledDuration: 103,
+		MemorySize:     128,
+		MaxMemoryUsed:  71,
+		InitDuration:   245.13,
+		ColdStart:      true,
+	}, report)
+
+	report, ok = parseLambdaReportLine(logEvents[3].Message)
+	assert.True(t, ok)
+	assert.False(t, report.ColdStart)
+	assert.Equal(t, float64(12.04), report.Duration)
+	assert.Equal(t, float64(72), report.MaxMemoryUsed)
+
+	_, ok = parseLambdaReportLine("REPORT RequestId: truncated")
+	assert.False(t, ok)
+}
+
+func TestLogEventsTransformLambdaReport(t *testing.T) {
+	defaultParseLambdaReport := parseLambdaReport
+	parseLambdaReport = true
+	defer func() { parseLambdaReport = defaultParseLambdaReport }()
+
+	logEvents := readLambdaReportEvents(t)
+	output := make(chan pdata.Logs)
+	metricsOutput := make(chan pdata.Metrics)
+	go transformLogEvents(context.Background(), "test account", "/aws/lambda/my-function", "2022/02/06/[$LATEST]abcd1234", logEvents, output, metricsOutput, nil)
+
+	logs := <-output
+	assert.Equal(t, len(logEvents), logs.LogRecordCount())
+	for range output {
+	}
+
+	metrics := <-metricsOutput
+	values := map[string][]float64{}
+	metricSlice := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metricSlice.Len(); i++ {
+		dataPoint := metricSlice.At(i).Gauge().DataPoints().At(0)
+		assertLogRecordHasAttribute(t, dataPoint.Attributes(), "faas.name", "my-function")
+		assertLogRecordDoNotHaveAttribute(t, dataPoint.Attributes(), "faas.execution")
+		values[metricSlice.At(i).Name()] = append(values[metricSlice.At(i).Name()], dataPoint.DoubleVal())
+	}
+	assert.Equal(t, map[string][]float64{
+		lambdaDurationMetricName:       {102.25, 12.04},
+		lambdaBilledDurationMetricName: {103, 13},
+		lambdaMemoryUsedMetricName:     {71, 72},
+		lambdaInitDurationMetricName:   {245.13},
+	}, values)
+
+	_, open := <-metricsOutput
+	assert.False(t, open)
+}
+
+func TestLogEventsTransformLambdaReportDisabled(t *testing.T) {
+	defaultParseLambdaReport := parseLambdaReport
+	parseLambdaReport = false
+	defer func() { parseLambdaReport = defaultParseLambdaReport }()
+
+	output := make(chan pdata.Logs)
+	metricsOutput := make(chan pdata.Metrics)
+	go transformLogEvents(context.Background(), "test account", "/aws/lambda/my-function", "2022/02/06/[$LATEST]abcd1234", readLambdaReportEvents(t), output, metricsOutput, nil)
+
+	for range output {
+	}
+	_, open := <-metricsOutput
+	assert.False(t, open)
+}
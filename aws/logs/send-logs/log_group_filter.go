@@ -0,0 +1,62 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"os"
+	"regexp"
+)
+
+// logGroupFilter restricts detection of a specific log format to matching log groups.
+// When the pattern is not configured, all log groups match.
+type logGroupFilter struct {
+	pattern  *regexp.Regexp
+	disabled bool
+}
+
+func newLogGroupFilter(envVar string, defaultPattern ...string) (filter logGroupFilter) {
+	pattern, exists := os.LookupEnv(envVar)
+	if !exists && len(defaultPattern) > 0 {
+		pattern = defaultPattern[0]
+	}
+	if pattern == "" {
+		return
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		appLogger.Error("Invalid ", envVar, " value, detection is disabled: ", err.Error())
+		filter.disabled = true
+		return
+	}
+	filter.pattern = compiled
+	return
+}
+
+// newRequiredLogGroupFilter disables detection until the pattern is configured
+func newRequiredLogGroupFilter(envVar string) logGroupFilter {
+	if os.Getenv(envVar) == "" {
+		return logGroupFilter{disabled: true}
+	}
+	return newLogGroupFilter(envVar)
+}
+
+func (f logGroupFilter) match(logGroup string) bool {
+	if f.disabled {
+		return false
+	}
+	return f.pattern == nil || f.pattern.MatchString(logGroup)
+}
@@ -22,21 +22,27 @@ import (
 
 type Logger interface {
 	Info(v ...interface {})
+	Warn(v ...interface {})
 	Error(v ...interface {})
 	Fatal(v ...interface {})
 }
 
 type logger struct {
-	infoLogger log.Logger
-	errorLogger log.Logger
+	infoLogger *log.Logger
+	warnLogger *log.Logger
+	errorLogger *log.Logger
 }
 
 func (l logger) Info(v ...interface {}) {
 	l.infoLogger.Println(v...)
 }
 
+func (l logger) Warn(v ...interface {}) {
+	l.warnLogger.Println(v...)
+}
+
 func (l logger) Error(v ...interface {}) {
-	l.infoLogger.Println(v...)
+	l.errorLogger.Println(v...)
 }
 
 func (l logger) Fatal(v ...interface {}) {
@@ -46,7 +52,8 @@ func (l logger) Fatal(v ...interface {}) {
 
 func NewLogger(prefix string) (Logger) {
 	return &logger {
-		infoLogger: *log.New(log.Writer(), prefix + " INFO ", log.Lmsgprefix),
-		errorLogger: *log.New(log.Writer(), prefix + " ERROR ", log.Lmsgprefix),
+		infoLogger: log.New(log.Writer(), prefix + " INFO ", log.Lmsgprefix),
+		warnLogger: log.New(log.Writer(), prefix + " WARN ", log.Lmsgprefix),
+		errorLogger: log.New(log.Writer(), prefix + " ERROR ", log.Lmsgprefix),
 	}
 }
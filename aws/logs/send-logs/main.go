@@ -17,26 +17,30 @@ package main
 
 import (
 	"context"
-	"crypto/tls"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"runtime/debug"
 	"send-logs/logger"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"encoding/base64"
 	"encoding/json"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/kms"
 	"go.opentelemetry.io/collector/model/otlpgrpc"
 	"go.opentelemetry.io/collector/model/pdata"
+	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -51,9 +55,13 @@ const (
 	awsLambdaInitTypeVar     = "AWS_LAMBDA_INITIALIZATION_TYPE"
 	awsRegionVar             = "AWS_REGION"
 	awsFunctionVersion       = "AWS_LAMBDA_FUNCTION_VERSION"
+	awsExecutionEnvVar       = "AWS_EXECUTION_ENV"
 	otlpEndpointVar          = "OTLP_ENDPOINT"
 	apiTokenVar              = "API_TOKEN"
 	useEncryptionVar         = "USE_ENCRYPTION"
+	maxLogsPerBatchVar       = "MAX_LOGS_PER_BATCH"
+	emitInvocationTraceVar   = "EMIT_INVOCATION_TRACE"
+	defaultMaxLogsPerBatch   = 1000
 	timestampMultiplier      = 1000000 // AWS Logs timestamp is in millisends since Jan 1 , 1970, OTEL Collector timestamp is in nanoseconds
 )
 
@@ -67,17 +75,26 @@ var (
 	endpoint                    string = os.Getenv(otlpEndpointVar) // encrypted when AWS_EXECUTION_ENV contains 'AWS_Lambda_'
 	apiToken                    string = os.Getenv(apiTokenVar)     // encrypted when AWS_EXECUTION_ENV contains 'AWS_Lambda_'
 	appLogger                          = logger.NewLogger("send-logs")
+	maxLogsPerBatch                    = int(getEnvInt(maxLogsPerBatchVar, defaultMaxLogsPerBatch))
 	kmsClient                   *kms.KMS
 	detectInstanceNameAndRegion = regexp.MustCompile(`(?P<Fargate>(fargate-))?(?P<Instance>(i-|ip-)[\w\-]+)\.(?P<Region>[\w\-]+)\.`)
 	instanceParamIndex          = detectInstanceNameAndRegion.SubexpIndex("Instance")
 	regionParamIndex            = detectInstanceNameAndRegion.SubexpIndex("Region")
 	fargateParamIndex           = detectInstanceNameAndRegion.SubexpIndex("Fargate")
+	detectEKSClusterName        = regexp.MustCompile(`^/aws/eks/(?P<ClusterName>[^/]+)/cluster$`)
+	eksClusterNameParamIndex    = detectEKSClusterName.SubexpIndex("ClusterName")
+	newLogsClient               = otlpgrpc.NewLogsClient
+	newTracesClient             = otlpgrpc.NewTracesClient
+	readRandom                  = rand.Read
+	emitInvocationTrace         = strings.EqualFold(os.Getenv(emitInvocationTraceVar), "true")
+	baseResourceAttrs           map[string]string // resource attributes of the Lambda execution environment, added to all forwarded logs
 )
 
 type cloudTrailEvent struct {
 	EventSource string `json:"eventSource"`
 	EventName   string `json:"eventName"`
 	Region      string `json:"awsRegion"`
+	ErrorCode   string `json:"errorCode"`
 }
 
 type ec2InstanceParameter struct {
@@ -137,12 +154,41 @@ type iEc2Event interface {
 	getEventType() string
 }
 
+type iCloudTrailEvent interface {
+	getErrorCode() string
+}
+
+// plain text log sources detected by the log group name
+type iLogGroupSource interface {
+	setResourceAttributes(reqBuilder OtlpRequestBuilder) OtlpRequestBuilder
+	addLogEntry(reqBuilder OtlpRequestBuilder, itemId string, timestamp int64, message string) OtlpRequestBuilder
+}
+
+// events from which metrics are derived
+type iMetricEvent interface {
+	addMetrics(metricsBuilder OtlpMetricsBuilder, timestamp int64)
+}
+
+// events which customize the forwarded log record body, severity or attributes
+type iLogRecordEvent interface {
+	getBody() string
+	getSeverity() (pdata.SeverityNumber, string)
+	getAttributes() map[string]interface{}
+}
+
+// events forwarded as several log records, e.g. EventBridge events carrying multiple findings
+type iLogRecordsEvent interface {
+	getLogRecords() []iLogRecordEvent
+}
+
 func init() {
 
 	if runningTests {
 		return
 	}
 
+	baseResourceAttrs = lambdaExecutionEnvironment()
+
 	if endpoint == "" || apiToken == "" {
 		appLogger.Fatal(fmt.Sprintf("Function execution parameters are not configured. Please set and encrypt %s and %s environmet variables", otlpEndpointVar, apiTokenVar))
 	}
@@ -158,6 +204,32 @@ func init() {
 	apiToken = decodeString(apiToken)
 }
 
+// lambdaExecutionEnvironment returns the resource attributes of the execution environment forwarding the logs.
+// cloud.region is the region of the function, log records carry the region of the forwarded event when it differs
+func lambdaExecutionEnvironment() map[string]string {
+	attrs := make(map[string]string)
+	for key, value := range map[string]string{
+		semconv.AttributeFaaSName:      functionName,
+		semconv.AttributeFaaSVersion:   lambdaVersion,
+		semconv.AttributeFaaSMaxMemory: os.Getenv(awsLambdaFunctionMemorySizeVar),
+		semconv.AttributeCloudRegion:   lambdaRegion,
+		"aws.lambda.runtime":           os.Getenv(awsExecutionEnvVar),
+	} {
+		if value != "" {
+			attrs[key] = value
+		}
+	}
+	return attrs
+}
+
+// SetLambdaExecutionEnvironment adds the execution environment resource attributes collected during init
+func SetLambdaExecutionEnvironment(rb OtlpRequestBuilder) OtlpRequestBuilder {
+	if len(baseResourceAttrs) == 0 {
+		return rb
+	}
+	return rb.BulkSetResourceAttributes(baseResourceAttrs)
+}
+
 func decodeString(encrypted string) string {
 	decodedBytes, err := base64.StdEncoding.DecodeString(encrypted)
 	if err != nil {
@@ -196,42 +268,74 @@ func extractEC2InstanceId(ec2Event *ec2CloudTrailEvent) (instanceId string, err
 }
 
 func handleEvent(ctx context.Context, event events.CloudwatchLogsEvent) (r string, err error) {
+	startTime := time.Now()
 	r = "failure"
+	var logsChan chan pdata.Logs
+	var metricsChan chan pdata.Metrics
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			appLogger.Error("PANIC in handleEvent: ", recovered, "\n", string(debug.Stack()))
+			r = "failure"
+			err = panicError(recovered)
+			// release the transformLogEvents goroutine blocked on the unbuffered channels
+			drainChannels(logsChan, metricsChan)
+		}
+	}()
+
 	datareq, err := event.AWSLogs.Parse()
 	if err != nil {
 		appLogger.Error("While parsing Cloudwatch Log event: ", err.Error())
 		return r, err
 	}
 
-	dialOption := grpc.WithInsecure()
-
-	if executingInAWS {
-		config := &tls.Config{}
-		dialOption = grpc.WithTransportCredentials(credentials.NewTLS(config))
+	var memoryMetrics OtlpMetricsBuilder
+	if reportMemoryMetrics {
+		memoryMetrics = NewOtlpMetricsBuilder().
+			SetCloudAccount(datareq.Owner).
+			SetLogGroup(datareq.LogGroup).
+			SetLogStream(datareq.LogStream)
+		reportMemoryUsage(memoryMetrics, "start")
 	}
 
-	conn, err := grpc.Dial(endpoint, dialOption)
+	conn, err := getClientConn(regionEndpoint(datareq.LogGroup, datareq.LogEvents))
 
 	if err != nil {
 		appLogger.Error("While connecting to otlp/gRPC endpoint: ", err.Error())
 		return r, err
 	}
 
-	defer conn.Close()
+	logsClient := newLogsClient(conn)
+	metricsClient := otlpgrpc.NewMetricsClient(conn)
+	logsChan = make(chan pdata.Logs)
+	metricsChan = make(chan pdata.Metrics)
+	var processedEvents int64
+	go transformLogEvents(ctx, datareq.Owner, datareq.LogGroup, datareq.LogStream, datareq.LogEvents, logsChan, metricsChan, &processedEvents)
 
-	logsClient := otlpgrpc.NewLogsClient(conn)
-	logsChan := make(chan pdata.Logs)
-	go transformLogEvents(datareq.Owner, datareq.LogGroup, datareq.LogStream, datareq.LogEvents, logsChan)
+	stopTimeoutWarning := startTimeoutWarning(ctx, datareq.LogGroup, func() int {
+		return len(datareq.LogEvents) - int(atomic.LoadInt64(&processedEvents))
+	})
+	defer stopTimeoutWarning()
 
 	errs := make([]error, 0)
 	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+apiToken)
 
-	for logsData := range logsChan {
-		logRequest := otlpgrpc.NewLogsRequest()
-		logRequest.SetLogs(logsData)
-		_, err = logsClient.Export(ctx, logRequest)
+	errs = append(errs, exportLogs(ctx, logsClient, logsChan)...)
+	for metricsData := range metricsChan {
+		metricsRequest := otlpgrpc.NewMetricsRequest()
+		metricsRequest.SetMetrics(metricsData)
+		_, err = metricsClient.Export(ctx, metricsRequest)
 		if err != nil {
-			appLogger.Error("While exporting log data: ", err.Error())
+			appLogger.Error("While exporting metrics data: ", err.Error())
+			errs = append(errs, err)
+		}
+	}
+	if memoryMetrics != nil {
+		reportMemoryUsage(memoryMetrics, "end")
+		metricsRequest := otlpgrpc.NewMetricsRequest()
+		metricsRequest.SetMetrics(memoryMetrics.GetMetrics())
+		_, err = metricsClient.Export(ctx, metricsRequest)
+		if err != nil {
+			appLogger.Error("While exporting memory metrics data: ", err.Error())
 			errs = append(errs, err)
 		}
 	}
@@ -240,36 +344,162 @@ func handleEvent(ctx context.Context, event events.CloudwatchLogsEvent) (r strin
 	} else {
 		err = errs[len(errs)-1]
 	}
+	if emitInvocationTrace {
+		emitInvocationSpan(ctx, conn, r, startTime)
+	}
 	appLogger.Info("Function execution result: ", r)
 
 	return r, err
 }
 
-func transformLogEvents(account, logGroup, logStream string, input []events.CloudwatchLogsLogEvent, output chan pdata.Logs) {
-	defer close(output)
-	reqBuilder := NewOtlpRequestBuilder().
+// emitInvocationSpan sends a span covering the invocation, marked as failed when the result is "failure"
+func emitInvocationSpan(ctx context.Context, conn *grpc.ClientConn, result string, startTime time.Time) {
+	var traceId [16]byte
+	var spanId [8]byte
+	if _, err := readRandom(traceId[:]); err != nil {
+		appLogger.Error("While generating invocation trace id, the span is not sent: ", err.Error())
+		return
+	}
+	if _, err := readRandom(spanId[:]); err != nil {
+		appLogger.Error("While generating invocation span id, the span is not sent: ", err.Error())
+		return
+	}
+
+	traces := pdata.NewTraces()
+	resourceSpans := traces.ResourceSpans().AppendEmpty()
+	resourceSpans.Resource().Attributes().UpsertString(semconv.AttributeCloudProvider, semconv.AttributeCloudProviderAWS)
+	resourceSpans.Resource().Attributes().UpsertString(semconv.AttributeServiceName, functionName)
+	for key, value := range baseResourceAttrs {
+		resourceSpans.Resource().Attributes().UpsertString(key, value)
+	}
+
+	span := resourceSpans.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName(functionName)
+	span.SetKind(pdata.SpanKindServer)
+	span.SetTraceID(pdata.NewTraceID(traceId))
+	span.SetSpanID(pdata.NewSpanID(spanId))
+	span.SetStartTimestamp(pdata.NewTimestampFromTime(startTime))
+	span.SetEndTimestamp(pdata.NewTimestampFromTime(time.Now()))
+	span.Attributes().UpsertString(semconv.AttributeFaaSTrigger, semconv.AttributeFaaSTriggerDatasource)
+	if lambdaContext, ok := lambdacontext.FromContext(ctx); ok {
+		span.Attributes().UpsertString("faas.invocation_id", lambdaContext.AwsRequestID)
+	}
+	if result == "failure" {
+		span.Status().SetCode(pdata.StatusCodeError)
+	} else {
+		span.Status().SetCode(pdata.StatusCodeOk)
+	}
+
+	tracesRequest := otlpgrpc.NewTracesRequest()
+	tracesRequest.SetTraces(traces)
+	if _, err := newTracesClient(conn).Export(ctx, tracesRequest); err != nil {
+		appLogger.Error("While exporting invocation trace: ", err.Error())
+	}
+}
+
+// exportLogs sends the logs until the channel is closed
+func exportLogs(ctx context.Context, logsClient otlpgrpc.LogsClient, logsChan chan pdata.Logs) (errs []error) {
+	for logsData := range logsChan {
+		logRequest := otlpgrpc.NewLogsRequest()
+		logRequest.SetLogs(logsData)
+		_, err := logsClient.Export(ctx, logRequest)
+		if err != nil {
+			appLogger.Error("While exporting log data: ", err.Error())
+			errs = append(errs, err)
+		}
+	}
+	return
+}
+
+// transformLogEvents stores the number of input events processed so far in processedEvents when it is not nil.
+// A single input event may produce several log records, or none when it is joined into a multiline entry.
+func transformLogEvents(ctx context.Context, account, logGroup, logStream string, input []events.CloudwatchLogsLogEvent, output chan pdata.Logs, metricsOutput chan pdata.Metrics, processedEvents *int64) {
+	metricsBuilder := NewOtlpMetricsBuilder().
 		SetCloudAccount(account).
 		SetLogGroup(logGroup).
 		SetLogStream(logStream)
+	// metrics are sent once all logs are sent and the logs channel is closed
+	defer sendMetrics(metricsBuilder, metricsOutput)
+	defer close(output)
+	// a panic in this goroutine would terminate the function, the logs channel is still closed by the deferred call above
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			appLogger.Error("PANIC in transformLogEvents: ", recovered, "\n", string(debug.Stack()))
+		}
+	}()
+	logGroupSource := detectLogGroupSource(logGroup, logStream)
+	eksClusterName := getEKSClusterName(logGroup)
+	newRequestBuilder := func() OtlpRequestBuilder {
+		reqBuilder := NewOtlpRequestBuilder().
+			SetCloudAccount(account).
+			SetLogGroup(logGroup).
+			SetLogStream(logStream).
+			SetLambdaInvocationContext(ctx)
+		if eksClusterName != "" {
+			reqBuilder.SetK8sClusterName(eksClusterName)
+		}
+		if logGroupSource != nil {
+			logGroupSource.setResourceAttributes(reqBuilder)
+		}
+		return reqBuilder
+	}
+	reqBuilder := newRequestBuilder()
+	validated := false
+	sendLogs := func(builder OtlpRequestBuilder) {
+		if !validated {
+			validated = true
+			if err := builder.Validate(); err != nil {
+				appLogger.Error("While validating log data: ", err.Error())
+			}
+		}
+		output <- builder.GetLogs()
+	}
+	addPlainLogEntry := func(itemId string, timestamp int64, message string) {
+		if reqBuilder.HasHostId() && !reqBuilder.MatchHostId(logStream) {
+			sendLogs(reqBuilder)
+			reqBuilder = newRequestBuilder()
+		}
 
-	for _, item := range input {
+		if logGroupSource != nil {
+			logGroupSource.addLogEntry(reqBuilder, itemId, timestamp, message)
+			return
+		}
+
+		reqBuilder.AddLogEntry(itemId, timestamp, message, lambdaRegion)
+	}
+	multiline := multilineBuffer{}
+	flushMultiline := func() {
+		if !multiline.isEmpty() {
+			addPlainLogEntry(multiline.itemId, multiline.timestamp, multiline.message())
+			multiline.reset()
+		}
+	}
+
+	for i, item := range input {
+		if processedEvents != nil {
+			atomic.StoreInt64(processedEvents, int64(i))
+		}
+
+		if maxLogsPerBatch > 0 && reqBuilder.GetLogCount() >= maxLogsPerBatch {
+			sendLogs(reqBuilder)
+			reqBuilder = reqBuilder.NextBatch()
+		}
 
 		// normalize timestamp to be accepted by OTEL
 		timestamp := item.Timestamp * timestampMultiplier
 
-		ok, ec2Event := parseMessage(item.Message)
+		ok, ec2Event := parseMessage(logGroup, item.Message)
 
 		if ok {
+			// structured events carry their own host and are never continuation lines
+			flushMultiline()
 			instanceId, err := ec2Event.getInstanceId()
 			if err == nil {
 				if !reqBuilder.HasHostId() {
 					reqBuilder.SetHostId(instanceId)
 				} else if !reqBuilder.MatchHostId(instanceId) {
-					output <- reqBuilder.GetLogs()
-					reqBuilder = NewOtlpRequestBuilder().
-						SetCloudAccount(account).
-						SetLogGroup(logGroup).
-						SetLogStream(logStream).
+					sendLogs(reqBuilder)
+					reqBuilder = newRequestBuilder().
 						SetHostId(instanceId)
 				}
 			}
@@ -281,42 +511,142 @@ func transformLogEvents(account, logGroup, logStream string, input []events.Clou
 					setKubernetesInfo(reqBuilder, k8sFargateLog)
 				} else if !reqBuilder.MatchContainerName(k8sFargateLog.ClusterUID, k8sFargateLog.Kubernetes.NamespaceName, k8sFargateLog.Kubernetes.PodName, k8sFargateLog.Kubernetes.ContainerName) {
 					// new container, send logs for previous container
-					output <- reqBuilder.GetLogs()
-					reqBuilder = setKubernetesInfo(
-						NewOtlpRequestBuilder().
-							SetCloudAccount(account).
-							SetLogGroup(logGroup).
-							SetLogStream(logStream),
-						k8sFargateLog)
+					sendLogs(reqBuilder)
+					reqBuilder = setKubernetesInfo(newRequestBuilder(), k8sFargateLog)
 				}
 
 				reqBuilder.AddLogEntry(item.ID, timestamp, k8sFargateLog.Log, ec2Event.getRegion(), map[string]interface{}{
 					"sw.k8s.log.type": k8sFargateLog.LogType,
 				})
 			} else {
-				reqBuilder.AddLogEntry(item.ID, timestamp, item.Message, ec2Event.getRegion())
+				addEventLogEntry(reqBuilder, item.ID, timestamp, item.Message, ec2Event)
+			}
+			if metricEvent, ok := ec2Event.(iMetricEvent); ok {
+				metricEvent.addMetrics(metricsBuilder, timestamp)
 			}
 			continue
 		}
 
-		if reqBuilder.HasHostId() && !reqBuilder.MatchHostId(logStream) {
-			output <- reqBuilder.GetLogs()
-			reqBuilder = NewOtlpRequestBuilder().
-				SetCloudAccount(account).
-				SetLogGroup(logGroup).
-				SetLogStream(logStream).
-				AddLogEntry(item.ID, item.Timestamp*timestampMultiplier, item.Message, lambdaRegion)
+		if parseLambdaReport {
+			if report, ok := parseLambdaReportLine(item.Message); ok {
+				report.addMetrics(metricsBuilder, logGroup, timestamp)
+			}
+		}
+
+		if multilineStartPattern != nil {
+			if !multiline.isEmpty() && !multilineStartPattern.MatchString(item.Message) {
+				multiline.append(item.Message)
+				continue
+			}
+			flushMultiline()
+			multiline.start(item.ID, timestamp, item.Message)
 			continue
+		}
+
+		addPlainLogEntry(item.ID, timestamp, item.Message)
+	}
+	flushMultiline()
+
+	if reqBuilder.HasLogEntries() {
+		sendLogs(reqBuilder)
+	}
+	if processedEvents != nil {
+		atomic.StoreInt64(processedEvents, int64(len(input)))
+	}
+}
+
+// drainChannels discards the remaining logs and metrics until transformLogEvents closes the channels
+func drainChannels(logsChan chan pdata.Logs, metricsChan chan pdata.Metrics) {
+	if logsChan != nil {
+		for range logsChan {
+		}
+	}
+	if metricsChan != nil {
+		for range metricsChan {
+		}
+	}
+}
 
+func panicError(recovered interface{}) error {
+	if err, ok := recovered.(error); ok {
+		return err
+	}
+	return fmt.Errorf("panic: %v", recovered)
+}
+
+func sendMetrics(metricsBuilder OtlpMetricsBuilder, metricsOutput chan pdata.Metrics) {
+	if metricsOutput == nil {
+		return
+	}
+	defer close(metricsOutput)
+	if metricsBuilder.GetMetricCount() > 0 {
+		metricsOutput <- metricsBuilder.GetMetrics()
+	}
+}
+
+// EKS control plane logs are sent to the /aws/eks/<cluster-name>/cluster log group
+func getEKSClusterName(logGroup string) string {
+	matches := detectEKSClusterName.FindStringSubmatch(logGroup)
+	if matches == nil {
+		return ""
+	}
+	return matches[eksClusterNameParamIndex]
+}
+
+// detect plain text log formats recognized by the log group name
+func detectLogGroupSource(logGroup, logStream string) iLogGroupSource {
+	if source, ok := newCodeBuildLogSource(logGroup, logStream); ok {
+		return source
+	}
+	if source, ok := newOpenSearchLogSource(logGroup); ok {
+		return source
+	}
+	if source, ok := newSageMakerLogSource(logGroup, logStream); ok {
+		return source
+	}
+	if source, ok := newBatchLogSource(logGroup, logStream); ok {
+		return source
+	}
+	if source, ok := newGlueLogSource(logGroup, logStream); ok {
+		return source
+	}
+	return nil
+}
+
+func addEventLogEntry(reqBuilder OtlpRequestBuilder, itemId string, timestamp int64, message string, event iEc2Event) OtlpRequestBuilder {
+	// events without any log records are forwarded as a single log entry instead of being dropped
+	if logRecordsEvent, ok := event.(iLogRecordsEvent); ok {
+		if logRecords := logRecordsEvent.getLogRecords(); len(logRecords) > 0 {
+			for _, logRecord := range logRecords {
+				addLogRecordEntry(reqBuilder, itemId, timestamp, message, event.getRegion(), logRecord)
+			}
+			return reqBuilder
 		}
+	}
+
+	logRecordEvent, ok := event.(iLogRecordEvent)
+	if !ok {
+		severity, severityText := getCloudTrailEventSeverity(event)
+		return reqBuilder.AddLogEntryWithSeverity(itemId, timestamp, message, event.getRegion(), severity, severityText)
+	}
+
+	return addLogRecordEntry(reqBuilder, itemId, timestamp, message, event.getRegion(), logRecordEvent)
+}
 
-		reqBuilder.AddLogEntry(item.ID, timestamp, item.Message, lambdaRegion)
+func addLogRecordEntry(reqBuilder OtlpRequestBuilder, itemId string, timestamp int64, message, region string, logRecord iLogRecordEvent) OtlpRequestBuilder {
+	if body := logRecord.getBody(); body != "" {
+		message = body
 	}
+	severity, severityText := logRecord.getSeverity()
+	return reqBuilder.AddLogEntryWithSeverity(itemId, timestamp, message, region, severity, severityText, logRecord.getAttributes())
+}
 
-	logs := reqBuilder.GetLogs()
-	if logs.ResourceLogs().Len() >= 0 {
-		output <- logs
+// failed CloudTrail API calls carry an error code, e.g. "AccessDenied", and are reported with ERROR severity
+func getCloudTrailEventSeverity(event iEc2Event) (pdata.SeverityNumber, string) {
+	if cloudTrail, ok := event.(iCloudTrailEvent); ok && cloudTrail.getErrorCode() != "" {
+		return pdata.SeverityNumberERROR, "ERROR"
 	}
+	return pdata.SeverityNumberUNDEFINED, ""
 }
 
 func setKubernetesInfo(reqBuilder OtlpRequestBuilder, k8sFargateLog *cloudInsightsAppLog) OtlpRequestBuilder {
@@ -378,7 +708,7 @@ func testJsonPath(jsonEvent map[string]interface{}, path string, values ...strin
 	return exists
 }
 
-func parseMessage(message string) (ok bool, result iEc2Event) {
+func parseMessage(logGroup, message string) (ok bool, result iEc2Event) {
 	ok = false
 	result = nil
 
@@ -409,6 +739,106 @@ func parseMessage(message string) (ok bool, result iEc2Event) {
 		}
 	}
 
+	if isAwsConfigLog(logGroup, jsonEvent) {
+		configLog := awsConfigLog{}
+		err := json.Unmarshal([]byte(message), &configLog)
+		if err == nil {
+			ok = true
+			result = &configLog
+			return
+		}
+	}
+
+	if isElastiCacheSlowLog(logGroup, jsonEvent) {
+		slowLog := elastiCacheSlowLog{}
+		err := json.Unmarshal([]byte(message), &slowLog)
+		if err == nil {
+			ok = true
+			result = &slowLog
+			return
+		}
+	}
+
+	if isDynamoDBStreamLog(logGroup, jsonEvent) {
+		streamLog := dynamoDBStreamLog{}
+		err := json.Unmarshal([]byte(message), &streamLog)
+		if err == nil {
+			ok = true
+			result = &streamLog
+			return
+		}
+	}
+
+	if isTransferFamilyLog(logGroup, jsonEvent) {
+		transferLog := transferFamilyLog{}
+		err := json.Unmarshal([]byte(message), &transferLog)
+		if err == nil {
+			ok = true
+			result = &transferLog
+			return
+		}
+	}
+
+	if isConnectCTR(logGroup, jsonEvent) {
+		ctr := connectCTR{}
+		err := json.Unmarshal([]byte(message), &ctr)
+		if err == nil {
+			ok = true
+			result = &ctr
+			return
+		}
+	}
+
+	if isSecurityHubEvent(logGroup, jsonEvent) {
+		securityHub := securityHubEvent{}
+		err := json.Unmarshal([]byte(message), &securityHub)
+		if err == nil {
+			ok = true
+			result = &securityHub
+			return
+		}
+	}
+
+	if isIotCoreLog(logGroup, jsonEvent) {
+		iotLog := iotCoreLog{}
+		err := json.Unmarshal([]byte(message), &iotLog)
+		if err == nil {
+			ok = true
+			result = &iotLog
+			return
+		}
+	}
+
+	if isNeptuneAuditLog(logGroup, jsonEvent) {
+		auditLog := neptuneAuditLog{}
+		err := json.Unmarshal([]byte(message), &auditLog)
+		if err == nil {
+			ok = true
+			result = &auditLog
+			return
+		}
+	}
+
+	if isWafLog(logGroup, jsonEvent) {
+		webAclLog := wafLog{}
+		err := json.Unmarshal([]byte(message), &webAclLog)
+		if err == nil {
+			ok = true
+			result = &webAclLog
+			return
+		}
+	}
+
+	if isAppMeshAccessLog(logGroup, jsonEvent) {
+		accessLog := appMeshAccessLog{}
+		err := json.Unmarshal([]byte(message), &accessLog)
+		if err == nil {
+			ok = true
+			result = &accessLog
+			return
+		}
+	}
+
 	if testJsonPath(jsonEvent, "ec2_instance_id") {
 		ciLog := cloudInsightsLog{}
 		err := json.Unmarshal([]byte(message), &ciLog)
@@ -475,13 +905,13 @@ func (evt *cloudInsightsLog) getEventType() (result string) {
 
 func (evt *cloudInsightsAppLog) parse() {
 	matches := detectInstanceNameAndRegion.FindStringSubmatch(evt.Kubernetes.Host)
-	if matches != nil {
-		if fargateParamIndex < len(matches) && matches[fargateParamIndex] != "" {
-			evt.parsedInstanceId = ""
-		} else if instanceParamIndex < len(matches) && matches[instanceParamIndex] != "" {
-			evt.parsedInstanceId = matches[instanceParamIndex]
-		}
+	if isFargateNode(evt.Kubernetes.Host, evt.Kubernetes.Labels) {
+		evt.parsedInstanceId = ""
+	} else if matches != nil && instanceParamIndex < len(matches) && matches[instanceParamIndex] != "" {
+		evt.parsedInstanceId = matches[instanceParamIndex]
+	}
 
+	if matches != nil {
 		if regionParamIndex < len(matches) && matches[regionParamIndex] != "" {
 			evt.parsedRegion = matches[regionParamIndex]
 		}
@@ -498,8 +928,7 @@ func (evt *cloudInsightsAppLog) getInstanceId() (result string, err error) {
 }
 
 func (evt *cloudInsightsAppLog) getEventType() (result string) {
-	matches := detectInstanceNameAndRegion.FindStringSubmatch(evt.Kubernetes.Host)
-	if matches != nil && fargateParamIndex < len(matches) && matches[fargateParamIndex] != "" {
+	if isFargateNode(evt.Kubernetes.Host, evt.Kubernetes.Labels) {
 		result = fargateEvent
 		return
 	}
@@ -551,6 +980,15 @@ func (evt *cloudTrailEvent) getEventType() (result string) {
 	return
 }
 
+func (evt *cloudTrailEvent) getErrorCode() (result string) {
+	result = evt.ErrorCode
+	return
+}
+
 func main() {
+	if cloudTrailLakeLogGroupName != "" {
+		lambda.Start(HandleCloudTrailLakeEvent)
+		return
+	}
 	lambda.Start(handleEvent)
 }
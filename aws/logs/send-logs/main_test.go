@@ -15,15 +15,22 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/otlpgrpc"
 	"go.opentelemetry.io/collector/model/pdata"
 	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+	"google.golang.org/grpc"
 )
 
 var _= (func() interface {} {
@@ -118,9 +125,15 @@ func TestMessageKindDetection(t *testing.T) {
     cloudInsightsLogMessage4, err := os.ReadFile("testdata/cloud_insights_app_fargate_log.json")
     assert.Nil(t, err)
     cloudTrailGenericMessage, err := os.ReadFile("testdata/event3.json")
+    assert.Nil(t, err)
+    awsConfigMessage, err := os.ReadFile("testdata/aws_config_change.json")
+    assert.Nil(t, err)
+    elastiCacheSlowLogMessage, err := os.ReadFile("testdata/elasticache_slow_log.json")
+    assert.Nil(t, err)
 
     testCases := [] struct {
         name string
+        logGroup string
         message string
         ok bool
         result iEc2Event
@@ -266,11 +279,50 @@ func TestMessageKindDetection(t *testing.T) {
             ec2InstanceId: "",
             region: "eu-west-3",
         },
+        {
+            name: "AWS Config configuration change message is detected and parsed",
+            logGroup: "aws-config-changes",
+            message: string(awsConfigMessage),
+            ok: true,
+            result: &awsConfigLog {
+                    ConfigurationItemStatus: "ResourceDeleted",
+                    ConfigurationItemCaptureTime: "2022-08-04T14:06:39.123Z",
+                    ResourceType: "AWS::EC2::Instance",
+                    ResourceId: "i-061bf37e959383a04",
+                    Region: "us-east-1",
+                    Configuration: json.RawMessage(`{"instanceId":"i-061bf37e959383a04","instanceType":"t2.micro","state":{"code":48,"name":"terminated"}}`),
+            },
+            ec2InstanceId: "i-061bf37e959383a04",
+            region: "us-east-1",
+        },
+        {
+            name: "AWS Config configuration change message outside of Config log groups detected as Default message kind",
+            logGroup: "/aws/lambda/my-function",
+            message: string(awsConfigMessage),
+            ok: false,
+            result: nil,
+        },
+        {
+            name: "ElastiCache slow log message is detected and parsed",
+            logGroup: "/aws/elasticache/my-redis-cluster",
+            message: string(elastiCacheSlowLogMessage),
+            ok: true,
+            result: &elastiCacheSlowLog {
+                    DurationMicroseconds: 250000,
+                    Command: []string{"KEYS", "session:*"},
+                    ClientAddress: "10.0.1.25:52132",
+                    NodeId: "my-redis-cluster-0001-001",
+            },
+            ec2InstanceId: "",
+            region: lambdaRegion,
+        },
     }
 
+    setAwsConfigLogGroupPattern(t, "^aws-config-")
+
     for _, tc := range testCases {
         t.Run(tc.name, func(t *testing.T) {
-            ok, result := parseMessage(tc.message)
+            ok, result := parseMessage(tc.logGroup, tc.message)
             assert.Equal(t, tc.ok, ok)
             assert.Equal(t, tc.result, result)
             if ok {
@@ -286,13 +338,77 @@ func TestMessageKindDetection(t *testing.T) {
 func TestMessageParsing(t *testing.T) {
     cloudTrailEc2Message, err := os.ReadFile("testdata/event1.json")
     assert.Nil(t, err)
-    ok, ec2Event := parseMessage(string(cloudTrailEc2Message))
+    ok, ec2Event := parseMessage("", string(cloudTrailEc2Message))
 
     assert.True(t, ok)
     id, err := ec2Event.getInstanceId()
     assert.Equal(t, "i-061bf37e959383a04", id)
 }
 
+func TestLambdaExecutionEnvironment(t *testing.T) {
+    originalFunctionName, originalVersion, originalRegion := functionName, lambdaVersion, lambdaRegion
+    functionName, lambdaVersion, lambdaRegion = "send-logs", "$LATEST", "us-east-1"
+    t.Setenv(awsLambdaFunctionMemorySizeVar, "256")
+    t.Setenv(awsExecutionEnvVar, "")
+    t.Cleanup(func() {
+        functionName, lambdaVersion, lambdaRegion = originalFunctionName, originalVersion, originalRegion
+        baseResourceAttrs = nil
+    })
+
+    baseResourceAttrs = lambdaExecutionEnvironment()
+    assert.Equal(t, map[string]string{
+        semconv.AttributeFaaSName: "send-logs",
+        semconv.AttributeFaaSVersion: "$LATEST",
+        semconv.AttributeFaaSMaxMemory: "256",
+        semconv.AttributeCloudRegion: "us-east-1",
+    }, baseResourceAttrs)
+
+    attrs := NewOtlpRequestBuilder().GetLogs().ResourceLogs().At(0).Resource().Attributes()
+    assertLogRecordHasAttribute(t, attrs, semconv.AttributeFaaSName, "send-logs")
+    assertLogRecordHasAttribute(t, attrs, semconv.AttributeCloudRegion, "us-east-1")
+
+    output := make(chan pdata.Logs)
+    go transformLogEvents(context.Background(), "test account", "test log group", "test log stream", []events.CloudwatchLogsLogEvent{{
+        ID:        "1",
+        Timestamp: time.Now().UnixMilli(),
+        Message:   "Hello, World",
+    }}, output, nil, nil)
+    logs := <-output
+    attrs = logs.ResourceLogs().At(0).Resource().Attributes()
+    assertLogRecordHasAttribute(t, attrs, semconv.AttributeFaaSName, "send-logs")
+    assertLogRecordHasAttribute(t, attrs, semconv.AttributeFaaSMaxMemory, "256")
+    assertLogRecordDoNotHaveAttribute(t, attrs, "aws.lambda.runtime")
+    assertLogRecordHasAttribute(t, attrs, semconv.AttributeCloudRegion, "us-east-1")
+    for range output {
+    }
+}
+
+func TestLogEventsTransformNoEvents(t *testing.T) {
+    output := make(chan pdata.Logs)
+    go transformLogEvents(context.Background(), "test account", "test log group", "test log stream", []events.CloudwatchLogsLogEvent{}, output, nil, nil)
+
+    _, ok := <-output
+    assert.False(t, ok, "No logs are sent for a log group without log events")
+}
+
+func TestLogEventsTransformEKSClusterName(t *testing.T) {
+    logEvents := []events.CloudwatchLogsLogEvent{{
+        ID:        "1",
+        Timestamp: time.Now().UnixMilli(),
+        Message:   "I0115 10:30:45.123456      10 controller.go:611] quota admission added evaluator",
+    }}
+
+    output := make(chan pdata.Logs)
+    go transformLogEvents(context.Background(), "test account", "/aws/eks/production/cluster", "kube-apiserver-4f1e2d3c5b6a7980", logEvents, output, nil, nil)
+    logs := <-output
+    assertLogRecordHasAttribute(t, logs.ResourceLogs().At(0).Resource().Attributes(), semconv.AttributeK8SClusterName, "production")
+    for range output {
+    }
+
+    assert.Equal(t, "", getEKSClusterName("/aws/eks/production/containers"))
+    assert.Equal(t, "", getEKSClusterName("/aws/lambda/production"))
+}
+
 func TestLogEventsTransform(t *testing.T) {
     logEvents := make([] events.CloudwatchLogsLogEvent, 0)
 
@@ -312,7 +428,7 @@ func TestLogEventsTransform(t *testing.T) {
 
     output := make(chan pdata.Logs)
 
-    go transformLogEvents("test account", "test log group", "i-12345678", logEvents, output)
+    go transformLogEvents(context.Background(), "test account", "test log group", "i-12345678", logEvents, output, nil, nil)
 
     testCases := [] struct {
         name string
@@ -425,7 +541,7 @@ func TestLogEventsTransformForFargateTwoDifferentContainers(t *testing.T) {
     inputLogEvents := []events.CloudwatchLogsLogEvent{logEvent, logEvent2}
 
     logsChan := make(chan pdata.Logs)
-    go transformLogEvents("123456789012", "/aws/lambda/MyFunction", "2022/02/06/[$LATEST]abcd1234", inputLogEvents, logsChan)
+    go transformLogEvents(context.Background(), "123456789012", "/aws/lambda/MyFunction", "2022/02/06/[$LATEST]abcd1234", inputLogEvents, logsChan, nil, nil)
     transformedLogs := <-logsChan
 
     assert.NotNil(t, transformedLogs)
@@ -526,4 +642,215 @@ func createCloudTrailCloudWatchEvent(logItemId, eventName, instanceId string) (e
         Message:   string(msg),
     }
     return
-}
\ No newline at end of file
+}
+
+func TestLogEventsTransformFailedCloudTrailEvent(t *testing.T) {
+    logEvents := []events.CloudwatchLogsLogEvent{
+        {
+            ID:        "1",
+            Timestamp: time.Now().Unix(),
+            Message:   `{"eventVersion":"1.08","eventSource":"s3.amazonaws.com","eventName":"GetObject","awsRegion":"us-east-1"}`,
+        },
+        {
+            ID:        "2",
+            Timestamp: time.Now().Unix(),
+            Message:   `{"eventVersion":"1.08","eventSource":"s3.amazonaws.com","eventName":"GetObject","awsRegion":"us-east-1","errorCode":"AccessDenied"}`,
+        },
+    }
+
+    output := make(chan pdata.Logs)
+    go transformLogEvents(context.Background(), "test account", "test log group", "test log stream", logEvents, output, nil, nil)
+    logs := <-output
+
+    logEntries := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+    assert.Equal(t, 2, logEntries.Len())
+    assert.Equal(t, pdata.SeverityNumberUNDEFINED, logEntries.At(0).SeverityNumber())
+    assert.Equal(t, pdata.SeverityNumberERROR, logEntries.At(1).SeverityNumber())
+    assert.Equal(t, "ERROR", logEntries.At(1).SeverityText())
+
+    for range output {
+    }
+}
+
+func setAwsConfigLogGroupPattern(t *testing.T, pattern string) {
+    defaultAwsConfigLogGroups := awsConfigLogGroups
+    t.Cleanup(func() { awsConfigLogGroups = defaultAwsConfigLogGroups })
+    t.Setenv(awsConfigLogGroupPatternVar, pattern)
+    awsConfigLogGroups = newRequiredLogGroupFilter(awsConfigLogGroupPatternVar)
+}
+
+func TestLogEventsTransformAwsConfigLog(t *testing.T) {
+    setAwsConfigLogGroupPattern(t, "^aws-config-")
+
+    awsConfigMessage, err := os.ReadFile("testdata/aws_config_change.json")
+    assert.Nil(t, err)
+
+    logEvents := []events.CloudwatchLogsLogEvent{
+        {
+            ID:        "1",
+            Timestamp: time.Now().Unix(),
+            Message:   string(awsConfigMessage),
+        },
+    }
+
+    output := make(chan pdata.Logs)
+    go transformLogEvents(context.Background(), "test account", "aws-config-changes", "test log stream", logEvents, output, nil, nil)
+    logs := <-output
+
+    logRecord := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+    attributes := logRecord.Attributes()
+    assert.Equal(t, `{"instanceId":"i-061bf37e959383a04","instanceType":"t2.micro","state":{"code":48,"name":"terminated"}}`, logRecord.Body().StringVal())
+    assert.Equal(t, pdata.SeverityNumberWARN, logRecord.SeverityNumber())
+    assertLogRecordHasAttribute(t, attributes, "cloud.resource_type", "AWS::EC2::Instance")
+    assertLogRecordHasAttribute(t, attributes, "cloud.resource_id", "i-061bf37e959383a04")
+    assertLogRecordHasAttribute(t, attributes, semconv.AttributeCloudRegion, "us-east-1")
+    assertLogRecordHasAttribute(t, logs.ResourceLogs().At(0).Resource().Attributes(), semconv.AttributeHostID, "i-061bf37e959383a04")
+
+    for range output {
+    }
+}
+
+func TestLogGroupFilter(t *testing.T) {
+    os.Setenv("TEST_LOG_GROUP_PATTERN", "^/aws/config/.*")
+    defer os.Unsetenv("TEST_LOG_GROUP_PATTERN")
+
+    filter := newLogGroupFilter("TEST_LOG_GROUP_PATTERN")
+    assert.True(t, filter.match("/aws/config/changes"))
+    assert.False(t, filter.match("/aws/lambda/test"))
+
+    assert.True(t, newLogGroupFilter("TEST_UNSET_LOG_GROUP_PATTERN").match("/aws/lambda/test"))
+    assert.True(t, newLogGroupFilter("TEST_UNSET_LOG_GROUP_PATTERN", "^/aws/codebuild/").match("/aws/codebuild/project"))
+    assert.False(t, newLogGroupFilter("TEST_UNSET_LOG_GROUP_PATTERN", "^/aws/codebuild/").match("/aws/lambda/test"))
+    assert.False(t, newRequiredLogGroupFilter("TEST_UNSET_LOG_GROUP_PATTERN").match("/aws/config/changes"))
+    assert.True(t, newRequiredLogGroupFilter("TEST_LOG_GROUP_PATTERN").match("/aws/config/changes"))
+
+    os.Setenv("TEST_LOG_GROUP_PATTERN", "([")
+    assert.False(t, newLogGroupFilter("TEST_LOG_GROUP_PATTERN").match("/aws/lambda/test"))
+}
+
+func TestLogEventsTransformMaxLogsPerBatch(t *testing.T) {
+    defaultMaxLogsPerBatch := maxLogsPerBatch
+    maxLogsPerBatch = 2
+    defer func() { maxLogsPerBatch = defaultMaxLogsPerBatch }()
+
+    logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+    for i := 0; i < 5; i++ {
+        logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+            ID:        "1",
+            Timestamp: time.Now().Unix(),
+            Message:   "Hello, World",
+        })
+    }
+
+    output := make(chan pdata.Logs)
+    go transformLogEvents(context.Background(), "test account", "test log group", "i-12345678", logEvents, output, nil, nil)
+
+    batchSizes := make([]int, 0)
+    for logs := range output {
+        assertLogRecordHasAttribute(t, logs.ResourceLogs().At(0).Resource().Attributes(), semconv.AttributeHostID, "i-12345678")
+        batchSizes = append(batchSizes, logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().Len())
+    }
+    assert.Equal(t, []int{2, 2, 1}, batchSizes)
+}
+
+type panickingLogsClient struct{}
+
+func (c panickingLogsClient) Export(ctx context.Context, request otlpgrpc.LogsRequest, opts ...grpc.CallOption) (otlpgrpc.LogsResponse, error) {
+    var logs []pdata.Logs
+    _ = logs[request.Logs().LogRecordCount()] // index out of range
+    return otlpgrpc.NewLogsResponse(), nil
+}
+
+func TestHandleEvent_PanicRecovery(t *testing.T) {
+    defaultNewLogsClient := newLogsClient
+    newLogsClient = func(conn *grpc.ClientConn) otlpgrpc.LogsClient { return panickingLogsClient{} }
+    defer func() { newLogsClient = defaultNewLogsClient }()
+    t.Cleanup(closeClientConns)
+
+    event := newCloudwatchLogsEvent(t, "test log group", "Hello, World")
+
+    var r string
+    var err error
+    assert.NotPanics(t, func() {
+        r, err = handleEvent(context.Background(), event)
+    })
+    assert.Equal(t, "failure", r)
+    assert.Error(t, err)
+}
+
+type capturingTracesClient struct {
+    requests []otlpgrpc.TracesRequest
+}
+
+func (c *capturingTracesClient) Export(ctx context.Context, request otlpgrpc.TracesRequest, opts ...grpc.CallOption) (otlpgrpc.TracesResponse, error) {
+    c.requests = append(c.requests, request)
+    return otlpgrpc.NewTracesResponse(), nil
+}
+
+func TestEmitInvocationSpan(t *testing.T) {
+    client := &capturingTracesClient{}
+    defaultNewTracesClient, defaultFunctionName := newTracesClient, functionName
+    newTracesClient = func(conn *grpc.ClientConn) otlpgrpc.TracesClient { return client }
+    functionName = "send-logs"
+    defer func() { newTracesClient, functionName = defaultNewTracesClient, defaultFunctionName }()
+
+    ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "8476a536-e9f4-11e8-9739-2dfe598c3fcd"})
+    startTime := time.Now().Add(-time.Second)
+
+    testCases := []struct {
+        result string
+        status pdata.StatusCode
+    }{
+        {"success", pdata.StatusCodeOk},
+        {"failure", pdata.StatusCodeError},
+    }
+
+    for i, tc := range testCases {
+        t.Run(tc.result, func(t *testing.T) {
+            emitInvocationSpan(ctx, nil, tc.result, startTime)
+            assert.Equal(t, i+1, len(client.requests))
+
+            traces := client.requests[i].Traces()
+            assert.Equal(t, 1, traces.SpanCount())
+            span := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+            assert.Equal(t, "send-logs", span.Name())
+            assert.Equal(t, tc.status, span.Status().Code())
+            assert.Equal(t, pdata.NewTimestampFromTime(startTime), span.StartTimestamp())
+            assert.True(t, span.EndTimestamp() > span.StartTimestamp())
+            assertLogRecordHasAttribute(t, span.Attributes(), "faas.invocation_id", "8476a536-e9f4-11e8-9739-2dfe598c3fcd")
+            assertLogRecordHasAttribute(t, span.Attributes(), semconv.AttributeFaaSTrigger, "datasource")
+        })
+    }
+}
+
+func TestEmitInvocationSpan_RandomFailure(t *testing.T) {
+    client := &capturingTracesClient{}
+    defaultNewTracesClient, defaultReadRandom := newTracesClient, readRandom
+    newTracesClient = func(conn *grpc.ClientConn) otlpgrpc.TracesClient { return client }
+    readRandom = func(b []byte) (int, error) { return 0, errors.New("entropy source unavailable") }
+    defer func() { newTracesClient, readRandom = defaultNewTracesClient, defaultReadRandom }()
+
+    emitInvocationSpan(context.Background(), nil, "success", time.Now())
+    assert.Equal(t, 0, len(client.requests))
+}
+
+func TestHandleEvent_PanicRecoveryReleasesTransform(t *testing.T) {
+    defaultNewLogsClient, defaultMaxLogsPerBatch := newLogsClient, maxLogsPerBatch
+    newLogsClient = func(conn *grpc.ClientConn) otlpgrpc.LogsClient { return panickingLogsClient{} }
+    maxLogsPerBatch = 1
+    defer func() { newLogsClient, maxLogsPerBatch = defaultNewLogsClient, defaultMaxLogsPerBatch }()
+    t.Cleanup(closeClientConns)
+
+    // more events than fit a batch, the transform goroutine still holds batches when the export panics
+    event := newCloudwatchLogsEvent(t, "test log group", "first", "second", "third")
+
+    r, err := handleEvent(context.Background(), event)
+    assert.Equal(t, "failure", r)
+    assert.Error(t, err)
+
+    assert.Eventually(t, func() bool {
+        stacks := make([]byte, 1<<20)
+        stacks = stacks[:runtime.Stack(stacks, true)]
+        return !strings.Contains(string(stacks), "send-logs.transformLogEvents(")
+    }, time.Second, 10*time.Millisecond, "transformLogEvents goroutine is still running")
+}
@@ -0,0 +1,61 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	reportMemoryMetricsVar         = "REPORT_MEMORY_METRICS"
+	memoryPressureWarnThresholdVar = "MEMORY_PRESSURE_WARN_THRESHOLD_MB"
+	awsLambdaFunctionMemorySizeVar = "AWS_LAMBDA_FUNCTION_MEMORY_SIZE"
+	memoryAllocMetricName          = "aws.lambda.memory.alloc_mb"
+	memorySysMetricName            = "aws.lambda.memory.sys_mb"
+	memoryGcPauseMetricName        = "aws.lambda.memory.gc_pause_ns"
+	bytesPerMegabyte               = 1024 * 1024
+)
+
+var (
+	reportMemoryMetrics         = strings.EqualFold(os.Getenv(reportMemoryMetricsVar), "true")
+	memoryPressureWarnThreshold = getEnvInt(memoryPressureWarnThresholdVar, getEnvInt(awsLambdaFunctionMemorySizeVar, 0)*80/100)
+)
+
+// add memory usage of the function as gauges, phase tells whether the usage was captured at the start or end of the invocation
+func reportMemoryUsage(metricsBuilder OtlpMetricsBuilder, phase string) {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	timestamp := time.Now().UnixNano()
+	allocMb := float64(memStats.Alloc) / bytesPerMegabyte
+	attributes := map[string]interface{}{
+		"faas.name": functionName,
+		"phase":     phase,
+	}
+
+	metricsBuilder.
+		AddGauge(memoryAllocMetricName, "MB", timestamp, allocMb, attributes).
+		AddGauge(memorySysMetricName, "MB", timestamp, float64(memStats.Sys)/bytesPerMegabyte, attributes).
+		AddGauge(memoryGcPauseMetricName, "ns", timestamp, float64(memStats.PauseNs[(memStats.NumGC+255)%256]), attributes)
+
+	if memoryPressureWarnThreshold > 0 && allocMb > float64(memoryPressureWarnThreshold) {
+		appLogger.Warn(fmt.Sprintf("Function memory pressure: alloc_mb=%.1f threshold_mb=%d phase=%s", allocMb, memoryPressureWarnThreshold, phase))
+	}
+}
@@ -0,0 +1,70 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"runtime"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestReportMemoryUsage_EmitsMetrics(t *testing.T) {
+	metricsBuilder := NewOtlpMetricsBuilder()
+	reportMemoryUsage(metricsBuilder, "start")
+	reportMemoryUsage(metricsBuilder, "end")
+
+	metrics := metricsBuilder.GetMetrics()
+	assert.Equal(t, 6, metrics.MetricCount())
+
+	values := map[string]float64{}
+	metricSlice := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metricSlice.Len(); i++ {
+		metric := metricSlice.At(i)
+		assert.Equal(t, pdata.MetricDataTypeGauge, metric.DataType())
+		dataPoint := metric.Gauge().DataPoints().At(0)
+		assert.GreaterOrEqual(t, dataPoint.DoubleVal(), float64(0))
+		phase, _ := dataPoint.Attributes().Get("phase")
+		values[metric.Name()+"/"+phase.StringVal()] = dataPoint.DoubleVal()
+	}
+
+	for _, name := range []string{memoryAllocMetricName, memorySysMetricName, memoryGcPauseMetricName} {
+		assert.Contains(t, values, name+"/start")
+		assert.Contains(t, values, name+"/end")
+	}
+	assert.Greater(t, values[memoryAllocMetricName+"/start"], float64(0))
+}
+
+func TestReportMemoryUsage_PressureWarning(t *testing.T) {
+	messages := captureLogMessages(t)
+	defaultThreshold := memoryPressureWarnThreshold
+	defer func() { memoryPressureWarnThreshold = defaultThreshold }()
+
+	memoryPressureWarnThreshold = 1 << 20
+	reportMemoryUsage(NewOtlpMetricsBuilder(), "start")
+	assert.Empty(t, messages)
+
+	memoryPressureWarnThreshold = 0
+	reportMemoryUsage(NewOtlpMetricsBuilder(), "start")
+	assert.Empty(t, messages)
+
+	ballast := make([]byte, 2*bytesPerMegabyte)
+	memoryPressureWarnThreshold = 1
+	reportMemoryUsage(NewOtlpMetricsBuilder(), "end")
+	assert.Contains(t, <-messages, "threshold_mb=1 phase=end")
+	runtime.KeepAlive(ballast)
+}
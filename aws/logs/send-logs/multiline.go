@@ -0,0 +1,69 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+const multilineStartPatternVar = "MULTILINE_START_PATTERN"
+
+var multilineStartPattern = newMultilineStartPattern(os.Getenv(multilineStartPatternVar))
+
+func newMultilineStartPattern(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		appLogger.Error("Invalid ", multilineStartPatternVar, " value, multi-line detection is disabled: ", err.Error())
+		return nil
+	}
+	return compiled
+}
+
+// multilineBuffer joins a log event matching the start pattern with the following continuation events,
+// e.g. a Java exception message with its stack trace
+type multilineBuffer struct {
+	itemId    string
+	timestamp int64
+	lines     []string
+}
+
+func (b *multilineBuffer) isEmpty() bool {
+	return len(b.lines) == 0
+}
+
+func (b *multilineBuffer) start(itemId string, timestamp int64, message string) {
+	b.itemId = itemId
+	b.timestamp = timestamp
+	b.lines = append(b.lines[:0], message)
+}
+
+func (b *multilineBuffer) append(message string) {
+	b.lines = append(b.lines, message)
+}
+
+func (b *multilineBuffer) message() string {
+	return strings.Join(b.lines, "\n")
+}
+
+func (b *multilineBuffer) reset() {
+	b.lines = b.lines[:0]
+}
@@ -0,0 +1,129 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func transformMultilineEvents(t *testing.T, messages ...string) (batches [][]string) {
+	defaultMultilineStartPattern := multilineStartPattern
+	multilineStartPattern = newMultilineStartPattern(`^\d{4}-\d{2}-\d{2} `)
+	defer func() { multilineStartPattern = defaultMultilineStartPattern }()
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for i, message := range messages {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        strconv.Itoa(i),
+			Timestamp: time.Now().UnixMilli(),
+			Message:   message,
+		})
+	}
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws/lambda/test", "test log stream", logEvents, output, nil, nil)
+
+	for logs := range output {
+		bodies := make([]string, 0)
+		logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		for i := 0; i < logRecords.Len(); i++ {
+			bodies = append(bodies, logRecords.At(i).Body().StringVal())
+		}
+		batches = append(batches, bodies)
+	}
+	return
+}
+
+func TestMultilineStartPattern(t *testing.T) {
+	assert.Nil(t, newMultilineStartPattern(""))
+	assert.Nil(t, newMultilineStartPattern("(["))
+	assert.NotNil(t, newMultilineStartPattern(`^\d{4}-`))
+}
+
+func TestLogEventsTransformMultilineSingleLines(t *testing.T) {
+	batches := transformMultilineEvents(t,
+		"2024-01-15 10:30:45 INFO Starting",
+		"2024-01-15 10:30:46 INFO Started",
+	)
+
+	assert.Equal(t, [][]string{{"2024-01-15 10:30:45 INFO Starting", "2024-01-15 10:30:46 INFO Started"}}, batches)
+}
+
+func TestLogEventsTransformMultilineJavaException(t *testing.T) {
+	batches := transformMultilineEvents(t,
+		"2024-01-15 10:30:45 INFO Processing order 42",
+		"2024-01-15 10:30:46 ERROR Order processing failed",
+		"java.lang.NullPointerException: customer is null",
+		"\tat com.example.OrderService.process(OrderService.java:87)",
+		"\tat com.example.OrderController.submit(OrderController.java:31)",
+		"2024-01-15 10:30:47 INFO Processing order 43",
+	)
+
+	assert.Equal(t, [][]string{{
+		"2024-01-15 10:30:45 INFO Processing order 42",
+		"2024-01-15 10:30:46 ERROR Order processing failed\n" +
+			"java.lang.NullPointerException: customer is null\n" +
+			"\tat com.example.OrderService.process(OrderService.java:87)\n" +
+			"\tat com.example.OrderController.submit(OrderController.java:31)",
+		"2024-01-15 10:30:47 INFO Processing order 43",
+	}}, batches)
+}
+
+func TestLogEventsTransformMultilineHostBoundary(t *testing.T) {
+	cloudInsightsMessage, err := os.ReadFile("testdata/cloud_insights_log.json")
+	assert.Nil(t, err)
+
+	batches := transformMultilineEvents(t,
+		"2024-01-15 10:30:46 ERROR Order processing failed",
+		"\tat com.example.OrderService.process(OrderService.java:87)",
+		string(cloudInsightsMessage),
+		"2024-01-15 10:30:47 INFO Processing order 43",
+		"\tat continuation after host boundary",
+	)
+
+	assert.Equal(t, [][]string{
+		{
+			"2024-01-15 10:30:46 ERROR Order processing failed\n\tat com.example.OrderService.process(OrderService.java:87)",
+			string(cloudInsightsMessage),
+		},
+		{
+			"2024-01-15 10:30:47 INFO Processing order 43\n\tat continuation after host boundary",
+		},
+	}, batches)
+}
+
+func TestLogEventsTransformMultilineDisabled(t *testing.T) {
+	logEvents := []events.CloudwatchLogsLogEvent{
+		{ID: "1", Timestamp: time.Now().UnixMilli(), Message: "2024-01-15 10:30:46 ERROR Order processing failed"},
+		{ID: "2", Timestamp: time.Now().UnixMilli(), Message: "\tat com.example.OrderService.process(OrderService.java:87)"},
+	}
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws/lambda/test", "test log stream", logEvents, output, nil, nil)
+
+	logs := <-output
+	assert.Equal(t, 2, logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().Len())
+	for range output {
+	}
+}
@@ -0,0 +1,114 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"net"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	neptuneLogGroupPatternVar  = "NEPTUNE_LOG_GROUP_PATTERN"
+	neptuneQueryTimeMetricName = "aws.neptune.query_time_ms"
+	neptuneSlowQueryType       = "QUERY_SLOW"
+	neptuneMaxStatementLength  = 512
+)
+
+var neptuneLogGroups = newLogGroupFilter(neptuneLogGroupPatternVar, "/aws/neptune/.*/audit")
+
+// Neptune audit log entry, type is one of CONNECT, QUERY, QUERY_SLOW or DISCONNECT
+type neptuneAuditLog struct {
+	Type       string  `json:"type"`
+	ClientHost string  `json:"clientHost"`
+	QueryId    string  `json:"queryId"`
+	User       string  `json:"user"`
+	DbHost     string  `json:"dbHost"`
+	QueryTime  float64 `json:"queryTime"`
+	Query      string  `json:"query"`
+}
+
+// queryId is not specific to Neptune, entries of other database logs are excluded
+func isNeptuneAuditLog(logGroup string, jsonEvent map[string]interface{}) bool {
+	return neptuneLogGroups.match(logGroup) &&
+		testJsonPath(jsonEvent, "queryId") &&
+		testJsonPath(jsonEvent, "clientHost") &&
+		!testJsonPath(jsonEvent, "dynamodb") &&
+		!testJsonPath(jsonEvent, "eventSource")
+}
+
+func (evt *neptuneAuditLog) getInstanceId() (result string, err error) {
+	err = errors.New("Neptune audit log doesn't contain EC2 Instance ID")
+	return
+}
+
+func (evt *neptuneAuditLog) getRegion() (result string) {
+	result = lambdaRegion
+	return
+}
+
+func (evt *neptuneAuditLog) getEventType() (result string) {
+	result = ec2Event
+	return
+}
+
+func (evt *neptuneAuditLog) getBody() (result string) {
+	return
+}
+
+func (evt *neptuneAuditLog) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	if evt.Type == neptuneSlowQueryType {
+		severity = pdata.SeverityNumberWARN
+		severityText = "WARN"
+	}
+	return
+}
+
+func (evt *neptuneAuditLog) getAttributes() (result map[string]interface{}) {
+	result = map[string]interface{}{
+		"db.system": "neptune",
+	}
+	if evt.Query != "" {
+		result["db.statement"] = truncateString(evt.Query, neptuneMaxStatementLength)
+	}
+	if evt.User != "" {
+		result["db.user"] = evt.User
+	}
+	if peerIp := evt.getClientIp(); peerIp != "" {
+		result["net.peer.ip"] = peerIp
+	}
+	return
+}
+
+func (evt *neptuneAuditLog) addMetrics(metricsBuilder OtlpMetricsBuilder, timestamp int64) {
+	if evt.QueryId == "" || evt.Type == "CONNECT" || evt.Type == "DISCONNECT" {
+		return
+	}
+	metricsBuilder.AddGauge(neptuneQueryTimeMetricName, "ms", timestamp, evt.QueryTime, map[string]interface{}{
+		"db.system":     "neptune",
+		"net.host.name": evt.DbHost,
+	})
+}
+
+// client host is reported as "ip:port"
+func (evt *neptuneAuditLog) getClientIp() string {
+	host, _, err := net.SplitHostPort(evt.ClientHost)
+	if err != nil {
+		return evt.ClientHost
+	}
+	return host
+}
@@ -0,0 +1,100 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestNeptuneAuditLogDetection(t *testing.T) {
+	message, err := os.ReadFile("testdata/neptune_audit_log.json")
+	assert.Nil(t, err)
+
+	ok, result := parseMessage("/aws/neptune/my-graph/audit", string(message))
+	assert.True(t, ok)
+	assert.IsType(t, &neptuneAuditLog{}, result)
+
+	ok, _ = parseMessage("/aws/lambda/my-function", string(message))
+	assert.False(t, ok)
+
+	ok, result = parseMessage("/aws/neptune/my-graph/audit", `{"queryId": "1", "clientHost": "10.0.2.14", "eventSource": "rds.amazonaws.com", "eventVersion": "1.08"}`)
+	assert.True(t, ok)
+	assert.IsType(t, &cloudTrailEvent{}, result)
+
+	ok, _ = parseMessage("/aws/neptune/my-graph/audit", `{"queryId": "1", "clientHost": "10.0.2.14", "dynamodb": {}}`)
+	assert.False(t, ok)
+}
+
+func TestNeptuneAuditLogStatementTruncation(t *testing.T) {
+	auditLog := neptuneAuditLog{Query: strings.Repeat("g.V().", 100) + "count()"}
+	statement := auditLog.getAttributes()["db.statement"].(string)
+	assert.Equal(t, neptuneMaxStatementLength, len(statement))
+	assert.True(t, strings.HasPrefix(auditLog.Query, statement))
+
+	assert.Equal(t, "żółw", truncateString("żółwie", 4))
+}
+
+func TestLogEventsTransformNeptuneAuditLog(t *testing.T) {
+	message, err := os.ReadFile("testdata/neptune_audit_log.json")
+	assert.Nil(t, err)
+	slowQueryMessage := string(message)
+	queryMessage := strings.Replace(slowQueryMessage, `"QUERY_SLOW"`, `"QUERY"`, 1)
+	connectMessage := strings.Replace(slowQueryMessage, `"QUERY_SLOW"`, `"CONNECT"`, 1)
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for i, message := range []string{connectMessage, queryMessage, slowQueryMessage} {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        strconv.Itoa(i),
+			Timestamp: time.Now().UnixMilli(),
+			Message:   message,
+		})
+	}
+
+	output := make(chan pdata.Logs)
+	metricsOutput := make(chan pdata.Metrics)
+	go transformLogEvents(context.Background(), "test account", "/aws/neptune/my-graph/audit", "my-graph-instance-1.audit", logEvents, output, metricsOutput, nil)
+
+	logs := <-output
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, 3, logRecords.Len())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, logRecords.At(1).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberWARN, logRecords.At(2).SeverityNumber())
+	attributes := logRecords.At(2).Attributes()
+	assertLogRecordHasAttribute(t, attributes, "db.system", "neptune")
+	assertLogRecordHasAttribute(t, attributes, "db.statement", "g.V().hasLabel('person').out('knows').out('knows').dedup().count()")
+	assertLogRecordHasAttribute(t, attributes, "db.user", "arn:aws:iam::123456789012:user/analyst")
+	assertLogRecordHasAttribute(t, attributes, "net.peer.ip", "10.0.2.14")
+	for range output {
+	}
+
+	metrics := <-metricsOutput
+	assert.Equal(t, 2, metrics.MetricCount())
+	metric := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(1)
+	assert.Equal(t, neptuneQueryTimeMetricName, metric.Name())
+	assert.Equal(t, float64(2350.5), metric.Gauge().DataPoints().At(0).DoubleVal())
+
+	_, open := <-metricsOutput
+	assert.False(t, open)
+}
@@ -0,0 +1,120 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	openSearchLogGroupPatternVar = "OPENSEARCH_LOG_GROUP_PATTERN"
+	openSearchMaxStatementLength = 512
+)
+
+var (
+	openSearchLogGroups     = newLogGroupFilter(openSearchLogGroupPatternVar, "/aws/opensearch/.*/.*-slowlogs")
+	detectOpenSearchSlowLog = regexp.MustCompile(`^\[[^\]]+\]\[(?P<Level>\w+)\s*\]\[(?P<Logger>[^\]]+)\]\s*(?:\[[^\]]*\]\s*)?\[(?P<Index>[^\]/]+)[^\]]*\](?:\[\d+\])?\s*took\[[^\]]*\],\s*took_millis\[(?P<TookMillis>\d+)\]`)
+	detectOpenSearchSource  = regexp.MustCompile(`source\[(?P<Query>.*?)\](?:,\s*id\[[^\]]*\])?,?\s*$`)
+	openSearchLevelIndex    = detectOpenSearchSlowLog.SubexpIndex("Level")
+	openSearchLoggerIndex   = detectOpenSearchSlowLog.SubexpIndex("Logger")
+	openSearchIndexIndex    = detectOpenSearchSlowLog.SubexpIndex("Index")
+	openSearchTookIndex     = detectOpenSearchSlowLog.SubexpIndex("TookMillis")
+	openSearchQueryIndex    = detectOpenSearchSource.SubexpIndex("Query")
+)
+
+// OpenSearch search and indexing slow logs published to CloudWatch Logs
+type openSearchLogSource struct{}
+
+// OpenSearch slow log entry, e.g.
+// [2024-01-15T10:30:45,123][WARN ][index.search.slowlog.query] [node-1] [logs-2024.01][0] took[1.2s], took_millis[1234], ... source[{"query":{...}}], id[],
+type openSearchSlowLog struct {
+	level      string
+	logger     string
+	index      string
+	tookMillis int64
+	query      string
+}
+
+func newOpenSearchLogSource(logGroup string) (source *openSearchLogSource, ok bool) {
+	if !openSearchLogGroups.match(logGroup) {
+		return
+	}
+	source = &openSearchLogSource{}
+	ok = true
+	return
+}
+
+func (src *openSearchLogSource) setResourceAttributes(reqBuilder OtlpRequestBuilder) OtlpRequestBuilder {
+	return reqBuilder
+}
+
+func (src *openSearchLogSource) addLogEntry(reqBuilder OtlpRequestBuilder, itemId string, timestamp int64, message string) OtlpRequestBuilder {
+	slowLog, ok := parseOpenSearchSlowLog(message)
+	if !ok {
+		return reqBuilder.AddLogEntry(itemId, timestamp, message, lambdaRegion)
+	}
+
+	severity, severityText := slowLog.getSeverity()
+	return reqBuilder.AddLogEntryWithSeverity(itemId, timestamp, message, lambdaRegion, severity, severityText, slowLog.getAttributes())
+}
+
+func parseOpenSearchSlowLog(message string) (slowLog openSearchSlowLog, ok bool) {
+	matches := detectOpenSearchSlowLog.FindStringSubmatch(message)
+	if matches == nil {
+		return
+	}
+
+	slowLog.level = matches[openSearchLevelIndex]
+	slowLog.logger = matches[openSearchLoggerIndex]
+	slowLog.index = matches[openSearchIndexIndex]
+	slowLog.tookMillis, _ = strconv.ParseInt(matches[openSearchTookIndex], 10, 64)
+	if sourceMatches := detectOpenSearchSource.FindStringSubmatch(message); sourceMatches != nil {
+		slowLog.query = sourceMatches[openSearchQueryIndex]
+	}
+	ok = true
+	return
+}
+
+func (slowLog openSearchSlowLog) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	switch strings.ToUpper(slowLog.level) {
+	case "WARN":
+		severity = pdata.SeverityNumberWARN
+	case "INFO":
+		severity = pdata.SeverityNumberINFO
+	case "DEBUG", "TRACE":
+		severity = pdata.SeverityNumberDEBUG
+	default:
+		return
+	}
+	severityText = strings.ToUpper(slowLog.level)
+	return
+}
+
+func (slowLog openSearchSlowLog) getAttributes() (result map[string]interface{}) {
+	result = map[string]interface{}{
+		"db.system":                   "opensearch",
+		"db.elasticsearch.index.name": slowLog.index,
+		"db.operation.took_ms":        int(slowLog.tookMillis),
+	}
+	if slowLog.query != "" {
+		result["db.statement"] = truncateString(slowLog.query, openSearchMaxStatementLength)
+	}
+	return
+}
@@ -0,0 +1,115 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestParseOpenSearchSlowLog(t *testing.T) {
+	testCases := []struct {
+		message    string
+		ok         bool
+		level      string
+		logger     string
+		index      string
+		tookMillis int64
+		query      string
+	}{
+		{
+			`[2024-01-15T10:30:45,123][WARN ][index.search.slowlog.query] [node-1] [logs-2024.01][0] took[1.2s], took_millis[1234], total_hits[42 hits], types[], stats[], search_type[QUERY_THEN_FETCH], total_shards[5], source[{"query":{"terms":{"tags":["a","b"]}}}], id[],`,
+			true, "WARN", "index.search.slowlog.query", "logs-2024.01", 1234, `{"query":{"terms":{"tags":["a","b"]}}}`,
+		},
+		{
+			`[2024-01-15T10:30:47,789][INFO ][index.indexing.slowlog.index] [orders/Xy3kQw8bRZ6mN2pL4tVa1A] took[512.3ms], took_millis[512], type[_doc], id[1001], routing[], source[{"order_id":1001}]`,
+			true, "INFO", "index.indexing.slowlog.index", "orders", 512, `{"order_id":1001}`,
+		},
+		{"[2024-01-15T10:30:45,123][WARN ][o.o.c.r.a.DiskThresholdMonitor] low disk watermark exceeded", false, "", "", "", 0, ""},
+		{"Unexpected line without slow log prefix", false, "", "", "", 0, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.message, func(t *testing.T) {
+			slowLog, ok := parseOpenSearchSlowLog(tc.message)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.level, slowLog.level)
+			assert.Equal(t, tc.logger, slowLog.logger)
+			assert.Equal(t, tc.index, slowLog.index)
+			assert.Equal(t, tc.tookMillis, slowLog.tookMillis)
+			assert.Equal(t, tc.query, slowLog.query)
+		})
+	}
+}
+
+func TestOpenSearchSlowLogStatementTruncated(t *testing.T) {
+	slowLog := openSearchSlowLog{query: strings.Repeat("x", openSearchMaxStatementLength+10)}
+	statement := slowLog.getAttributes()["db.statement"].(string)
+	assert.Equal(t, openSearchMaxStatementLength, len(statement))
+}
+
+func TestOpenSearchLogSourceDetection(t *testing.T) {
+	_, ok := newOpenSearchLogSource("/aws/opensearch/domains/my-domain-search-slowlogs")
+	assert.True(t, ok)
+
+	_, ok = newOpenSearchLogSource("/aws/opensearch/domains/my-domain-application-logs")
+	assert.False(t, ok)
+}
+
+func TestLogEventsTransformOpenSearchSlowLog(t *testing.T) {
+	data, err := os.ReadFile("testdata/opensearch_slow_log.txt")
+	assert.Nil(t, err)
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        "1",
+			Timestamp: time.Now().Unix(),
+			Message:   line,
+		})
+	}
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws/opensearch/domains/my-domain-search-slowlogs", "es-slowlogs", logEvents, output, nil, nil)
+	logs := <-output
+
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, len(logEvents), logRecords.Len())
+
+	attributes := logRecords.At(0).Attributes()
+	assertLogRecordHasAttribute(t, attributes, "db.system", "opensearch")
+	assertLogRecordHasAttribute(t, attributes, "db.elasticsearch.index.name", "logs-2024.01")
+	assertLogRecordHasAttribute(t, attributes, "db.statement", `{"query":{"bool":{"must":[{"match":{"message":"timeout"}}]}}}`)
+	tookMillis, ok := attributes.Get("db.operation.took_ms")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234), tookMillis.IntVal())
+	assert.Equal(t, pdata.SeverityNumberWARN, logRecords.At(0).SeverityNumber())
+
+	assert.Equal(t, pdata.SeverityNumberDEBUG, logRecords.At(1).SeverityNumber())
+	assertLogRecordHasAttribute(t, logRecords.At(2).Attributes(), "db.elasticsearch.index.name", "orders")
+	assert.Equal(t, pdata.SeverityNumberINFO, logRecords.At(2).SeverityNumber())
+	assertLogRecordDoNotHaveAttribute(t, logRecords.At(3).Attributes(), "db.system")
+
+	for range output {
+	}
+}
@@ -0,0 +1,154 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+)
+
+// OtlpMetricsBuilder accumulates metrics derived from log events forwarded by the function
+type OtlpMetricsBuilder interface {
+	SetCloudAccount(account string) OtlpMetricsBuilder
+	SetLogGroup(logGroup string) OtlpMetricsBuilder
+	SetLogStream(logStream string) OtlpMetricsBuilder
+	AddGauge(name, unit string, timestamp int64, value float64, attributes ...map[string]interface{}) OtlpMetricsBuilder
+	AddCounter(name, unit string, timestamp int64, value int64, attributes ...map[string]interface{}) OtlpMetricsBuilder
+	GetMetricCount() int
+	GetMetrics() pdata.Metrics
+}
+
+type otlpMetricsBuilder struct {
+	metrics      pdata.Metrics
+	resMetrics   pdata.ResourceMetrics
+	instrMetrics pdata.InstrumentationLibraryMetrics
+	counters     map[string]pdata.NumberDataPoint
+}
+
+func NewOtlpMetricsBuilder() (builder OtlpMetricsBuilder) {
+	metrics := pdata.NewMetrics()
+	resMetrics := metrics.ResourceMetrics().AppendEmpty()
+	resMetrics.SetSchemaUrl(semconv.SchemaURL)
+	instrMetrics := resMetrics.InstrumentationLibraryMetrics().AppendEmpty()
+	builder = &otlpMetricsBuilder{metrics: metrics, resMetrics: resMetrics, instrMetrics: instrMetrics, counters: map[string]pdata.NumberDataPoint{}}
+	return
+}
+
+func (mb *otlpMetricsBuilder) SetCloudAccount(account string) (builder OtlpMetricsBuilder) {
+	mb.resMetrics.Resource().Attributes().UpsertString(semconv.AttributeCloudAccountID, account)
+	builder = mb
+	return
+}
+
+func (mb *otlpMetricsBuilder) SetLogGroup(logGroup string) (builder OtlpMetricsBuilder) {
+	mb.resMetrics.Resource().Attributes().UpsertString(semconv.AttributeAWSLogGroupNames, logGroup)
+	builder = mb
+	return
+}
+
+func (mb *otlpMetricsBuilder) SetLogStream(logStream string) (builder OtlpMetricsBuilder) {
+	mb.resMetrics.Resource().Attributes().UpsertString(semconv.AttributeAWSLogStreamNames, logStream)
+	builder = mb
+	return
+}
+
+func (mb *otlpMetricsBuilder) AddGauge(name, unit string, timestamp int64, value float64, attributes ...map[string]interface{}) (builder OtlpMetricsBuilder) {
+	metric := mb.instrMetrics.Metrics().AppendEmpty()
+	metric.SetName(name)
+	metric.SetUnit(unit)
+	metric.SetDataType(pdata.MetricDataTypeGauge)
+
+	dataPoint := metric.Gauge().DataPoints().AppendEmpty()
+	dataPoint.SetTimestamp(pdata.Timestamp(timestamp))
+	dataPoint.SetDoubleVal(value)
+	setDataPointAttributes(dataPoint, attributes...)
+
+	builder = mb
+	return
+}
+
+// AddCounter accumulates the value into a delta sum identified by the metric name and attributes
+func (mb *otlpMetricsBuilder) AddCounter(name, unit string, timestamp int64, value int64, attributes ...map[string]interface{}) (builder OtlpMetricsBuilder) {
+	builder = mb
+	key := counterKey(name, attributes...)
+	if dataPoint, exists := mb.counters[key]; exists {
+		dataPoint.SetIntVal(dataPoint.IntVal() + value)
+		if pdata.Timestamp(timestamp) < dataPoint.StartTimestamp() {
+			dataPoint.SetStartTimestamp(pdata.Timestamp(timestamp))
+		}
+		if pdata.Timestamp(timestamp) > dataPoint.Timestamp() {
+			dataPoint.SetTimestamp(pdata.Timestamp(timestamp))
+		}
+		return
+	}
+
+	metric := mb.instrMetrics.Metrics().AppendEmpty()
+	metric.SetName(name)
+	metric.SetUnit(unit)
+	metric.SetDataType(pdata.MetricDataTypeSum)
+	metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+	metric.Sum().SetIsMonotonic(true)
+
+	dataPoint := metric.Sum().DataPoints().AppendEmpty()
+	dataPoint.SetStartTimestamp(pdata.Timestamp(timestamp))
+	dataPoint.SetTimestamp(pdata.Timestamp(timestamp))
+	dataPoint.SetIntVal(value)
+	setDataPointAttributes(dataPoint, attributes...)
+	mb.counters[key] = dataPoint
+	return
+}
+
+func (mb *otlpMetricsBuilder) GetMetricCount() int {
+	return mb.instrMetrics.Metrics().Len()
+}
+
+func (mb *otlpMetricsBuilder) GetMetrics() (metrics pdata.Metrics) {
+	metrics = mb.metrics
+	mb.resMetrics.Resource().Attributes().InsertString(semconv.AttributeCloudProvider, semconv.AttributeCloudProviderAWS)
+	return
+}
+
+func setDataPointAttributes(dataPoint pdata.NumberDataPoint, attributes ...map[string]interface{}) {
+	for _, attrs := range attributes {
+		for key, value := range attrs {
+			switch v := value.(type) {
+			case string:
+				dataPoint.Attributes().UpsertString(key, v)
+			case int:
+				dataPoint.Attributes().UpsertInt(key, int64(v))
+			case int64:
+				dataPoint.Attributes().UpsertInt(key, v)
+			case float64:
+				dataPoint.Attributes().UpsertDouble(key, v)
+			}
+		}
+	}
+}
+
+func counterKey(name string, attributes ...map[string]interface{}) string {
+	pairs := make([]string, 0)
+	for _, attrs := range attributes {
+		for key, value := range attrs {
+			pairs = append(pairs, fmt.Sprintf("%s=%v", key, value))
+		}
+	}
+	sort.Strings(pairs)
+	return name + "|" + strings.Join(pairs, "|")
+}
@@ -0,0 +1,77 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+)
+
+func TestOtlpMetricsBuilder(t *testing.T) {
+	mb := NewOtlpMetricsBuilder().
+		SetCloudAccount("test account").
+		SetLogGroup("test group").
+		SetLogStream("test stream")
+
+	assert.Equal(t, 0, mb.GetMetricCount())
+
+	timestamp := time.Now().UnixNano()
+	mb.AddGauge("test.gauge", "ms", timestamp, 42.5, map[string]interface{}{
+		"string": "value",
+		"int":    7,
+	})
+	assert.Equal(t, 1, mb.GetMetricCount())
+
+	metrics := mb.GetMetrics()
+	attrs := metrics.ResourceMetrics().At(0).Resource().Attributes().AsRaw()
+	expectedAttrs := map[string]interface{}{
+		semconv.AttributeCloudProvider:     semconv.AttributeCloudProviderAWS,
+		semconv.AttributeCloudAccountID:    "test account",
+		semconv.AttributeAWSLogGroupNames:  "test group",
+		semconv.AttributeAWSLogStreamNames: "test stream",
+	}
+	assert.Equal(t, expectedAttrs, attrs)
+
+	metric := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "test.gauge", metric.Name())
+	assert.Equal(t, "ms", metric.Unit())
+	dataPoint := metric.Gauge().DataPoints().At(0)
+	assert.Equal(t, pdata.Timestamp(timestamp), dataPoint.Timestamp())
+	assert.Equal(t, 42.5, dataPoint.DoubleVal())
+	assert.Equal(t, map[string]interface{}{"string": "value", "int": int64(7)}, dataPoint.Attributes().AsRaw())
+}
+
+func TestOtlpMetricsBuilderCounter(t *testing.T) {
+	mb := NewOtlpMetricsBuilder()
+
+	mb.AddCounter("test.counter", "1", 200, 1, map[string]interface{}{"operation": "INSERT"})
+	mb.AddCounter("test.counter", "1", 100, 2, map[string]interface{}{"operation": "INSERT"})
+	mb.AddCounter("test.counter", "1", 300, 1, map[string]interface{}{"operation": "REMOVE"})
+	assert.Equal(t, 2, mb.GetMetricCount())
+
+	metrics := mb.GetMetrics().ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	insert := metrics.At(0).Sum()
+	assert.Equal(t, pdata.MetricAggregationTemporalityDelta, insert.AggregationTemporality())
+	assert.True(t, insert.IsMonotonic())
+	assert.Equal(t, int64(3), insert.DataPoints().At(0).IntVal())
+	assert.Equal(t, pdata.Timestamp(100), insert.DataPoints().At(0).StartTimestamp())
+	assert.Equal(t, pdata.Timestamp(200), insert.DataPoints().At(0).Timestamp())
+	assert.Equal(t, int64(1), metrics.At(1).Sum().DataPoints().At(0).IntVal())
+}
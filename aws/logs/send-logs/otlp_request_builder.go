@@ -16,14 +16,28 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
 	"regexp"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
 
 	"go.opentelemetry.io/collector/model/pdata"
 	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
 )
+
+const (
+    logBodyFormatVar = "LOG_BODY_FORMAT"
+    logBodyFormatJson = "json"
+)
+
 var (
     detectHostIdRegExp = regexp.MustCompile(`^(?P<HostId>(i-|ip-)[\w\-]+)`)
     detectRegionRegExp = regexp.MustCompile(`(?P<Region>\w{2}-\w+-\d+)`)
+    jsonLogBody = strings.EqualFold(os.Getenv(logBodyFormatVar), logBodyFormatJson)
 )
 type OtlpRequestBuilder interface {
     SetHostId(hostId string) (OtlpRequestBuilder)
@@ -31,14 +45,20 @@ type OtlpRequestBuilder interface {
     SetLogGroup(logGroup string) (OtlpRequestBuilder)
     SetLogStream(logStream string) (OtlpRequestBuilder)
     AddLogEntry(entryId string, timestamp int64, message, region string, attributes ...map[string]interface{}) (OtlpRequestBuilder)
+    AddLogEntryWithSeverity(entryId string, timestamp int64, message, region string, severity pdata.SeverityNumber, severityText string, attributes ...map[string]interface{}) (OtlpRequestBuilder)
     MatchHostId(hostId string) (bool)
     HasHostId() (bool)
     GetLogs() pdata.Logs
+    GetLogCount() (int)
+    HasLogEntries() (bool)
+    Validate() (error)
+    NextBatch() (OtlpRequestBuilder)
     HasContainerName() (bool)
     MatchContainerName(clusterUid string, namespaceName string, podName string, containerName string) (bool)
     SetKubernetesPodName(podName string) (OtlpRequestBuilder)
     SetKubernetesNamespaceName(namespaceName string) (OtlpRequestBuilder)
     SetKubernetesClusterUid(clusterUid string) (OtlpRequestBuilder)
+    SetK8sClusterName(clusterName string) (OtlpRequestBuilder)
     SetKubernetesContainerName(containerName string) (OtlpRequestBuilder)
     SetKubernetesContainerImage(containerImage string) (OtlpRequestBuilder)
     SetKubernetesPodUID(podUID string) (OtlpRequestBuilder)
@@ -48,6 +68,19 @@ type OtlpRequestBuilder interface {
     SetKubernetesPodAnnotations(podAnnotations map[string]string) (OtlpRequestBuilder)
     SetKubernetesManifestVersion(manifestVersion string, defaultVersion string) (OtlpRequestBuilder)
     SetOtelAttributes(podName string, containerName string) (OtlpRequestBuilder)
+    SetCicdPipelineName(pipelineName string) (OtlpRequestBuilder)
+    SetCicdPipelineRunId(runId string) (OtlpRequestBuilder)
+    SetServiceName(serviceName string) (OtlpRequestBuilder)
+    SetSageMakerTrainingJobName(jobName string) (OtlpRequestBuilder)
+    SetBatchJobDefinitionName(jobDefinitionName string) (OtlpRequestBuilder)
+    SetBatchJobId(jobId string) (OtlpRequestBuilder)
+    SetGlueJobName(jobName string) (OtlpRequestBuilder)
+    SetGlueJobRunId(jobRunId string) (OtlpRequestBuilder)
+    SetFaaSInvocationId(id string) (OtlpRequestBuilder)
+    BulkSetResourceAttributes(attributes map[string]string) (OtlpRequestBuilder)
+    SetFaaSInstance(arn string) (OtlpRequestBuilder)
+    SetLambdaInvocationContext(ctx context.Context) (OtlpRequestBuilder)
+    SetDefaultSeverity(severity pdata.SeverityNumber, severityText string) (OtlpRequestBuilder)
 }
 
 type otlpRequestBuilder struct {
@@ -58,6 +91,8 @@ type otlpRequestBuilder struct {
     hostId string
     parsedRegion string
     parsedHostId string
+    defaultSeverity pdata.SeverityNumber
+    defaultSeverityText string
 }
 
 func NewOtlpRequestBuilder() (builder OtlpRequestBuilder){
@@ -65,7 +100,7 @@ func NewOtlpRequestBuilder() (builder OtlpRequestBuilder){
     resLogs := logs.ResourceLogs().AppendEmpty()
     resLogs.SetSchemaUrl(semconv.SchemaURL)
     instrLogsSlice := resLogs.InstrumentationLibraryLogs()
-    builder = &otlpRequestBuilder{ logs :  logs, resLogs: resLogs, instrLogsSlice: instrLogsSlice}
+    builder = SetLambdaExecutionEnvironment(&otlpRequestBuilder{ logs :  logs, resLogs: resLogs, instrLogsSlice: instrLogsSlice})
     return
 }
 
@@ -152,6 +187,13 @@ func (rb * otlpRequestBuilder) SetKubernetesClusterUid(clusterUid string) (build
     return
 }
 
+func (rb * otlpRequestBuilder) SetK8sClusterName(clusterName string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString(semconv.AttributeK8SClusterName, clusterName)
+    builder = rb
+    return
+}
+
 func (rb * otlpRequestBuilder) SetKubernetesContainerName(containerName string) (builder OtlpRequestBuilder) {
     attrs := rb.resLogs.Resource().Attributes()
     attrs.UpsertString(semconv.AttributeK8SContainerName, containerName)
@@ -225,6 +267,102 @@ func (rb * otlpRequestBuilder) SetOtelAttributes(podName string, containerName s
     return
 }
 
+func (rb * otlpRequestBuilder) SetCicdPipelineName(pipelineName string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString("cicd.pipeline.name", pipelineName)
+    builder = rb
+    return
+}
+
+func (rb * otlpRequestBuilder) SetCicdPipelineRunId(runId string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString("cicd.pipeline.run.id", runId)
+    builder = rb
+    return
+}
+
+func (rb * otlpRequestBuilder) SetServiceName(serviceName string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString(semconv.AttributeServiceName, serviceName)
+    builder = rb
+    return
+}
+
+func (rb * otlpRequestBuilder) SetSageMakerTrainingJobName(jobName string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString("aws.sagemaker.training_job_name", jobName)
+    builder = rb
+    return
+}
+
+func (rb * otlpRequestBuilder) SetBatchJobDefinitionName(jobDefinitionName string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString("aws.batch.job_definition_name", jobDefinitionName)
+    builder = rb
+    return
+}
+
+func (rb * otlpRequestBuilder) SetBatchJobId(jobId string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString("aws.batch.job_id", jobId)
+    builder = rb
+    return
+}
+
+func (rb * otlpRequestBuilder) SetGlueJobName(jobName string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString("aws.glue.job_name", jobName)
+    builder = rb
+    return
+}
+
+func (rb * otlpRequestBuilder) SetGlueJobRunId(jobRunId string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString("aws.glue.job_run_id", jobRunId)
+    builder = rb
+    return
+}
+
+func (rb * otlpRequestBuilder) BulkSetResourceAttributes(attributes map[string]string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    for key, value := range attributes {
+        attrs.UpsertString(key, value)
+    }
+    builder = rb
+    return
+}
+
+func (rb * otlpRequestBuilder) SetFaaSInvocationId(id string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString("faas.invocation_id", id)
+    builder = rb
+    return
+}
+
+func (rb * otlpRequestBuilder) SetFaaSInstance(arn string) (builder OtlpRequestBuilder) {
+    attrs := rb.resLogs.Resource().Attributes()
+    attrs.UpsertString(semconv.AttributeFaaSInstance, arn)
+    builder = rb
+    return
+}
+
+// SetLambdaInvocationContext sets the request ID and function ARN of the Lambda invocation forwarding the logs
+func (rb * otlpRequestBuilder) SetLambdaInvocationContext(ctx context.Context) (builder OtlpRequestBuilder) {
+    builder = rb
+    lambdaContext, ok := lambdacontext.FromContext(ctx)
+    if !ok {
+        return
+    }
+
+    if lambdaContext.AwsRequestID != "" {
+        rb.SetFaaSInvocationId(lambdaContext.AwsRequestID)
+    }
+    if lambdaContext.InvokedFunctionArn != "" {
+        rb.SetFaaSInstance(lambdaContext.InvokedFunctionArn)
+    }
+    return
+}
+
 func (rb * otlpRequestBuilder) SetLogStream(logStream string) (builder OtlpRequestBuilder) {
     attrs := rb.resLogs.Resource().Attributes()
     attrs.InsertString(semconv.AttributeAWSLogStreamNames, logStream)
@@ -256,13 +394,29 @@ func (rb *otlpRequestBuilder) HasHostId() (bool) {
 }
 
 func (rb *otlpRequestBuilder) AddLogEntry(itemId string, timestamp int64, message, region string, attributes ...map[string]interface{}) (builder OtlpRequestBuilder) {
+    return rb.AddLogEntryWithSeverity(itemId, timestamp, message, region, pdata.SeverityNumberUNDEFINED, "", attributes...)
+}
+
+// AddLogEntryWithSeverity adds a log entry with the given severity, the default severity is applied when it is undefined
+func (rb *otlpRequestBuilder) AddLogEntryWithSeverity(itemId string, timestamp int64, message, region string, severity pdata.SeverityNumber, severityText string, attributes ...map[string]interface{}) (builder OtlpRequestBuilder) {
     if rb.instrLogsSlice.Len()== 0 {
         rb.instrLogs = rb.instrLogsSlice.AppendEmpty()
     }
     logEntry := rb.instrLogs.Logs().AppendEmpty()
     logEntry.SetName(itemId)
     logEntry.SetTimestamp(pdata.Timestamp(timestamp))
-    logEntry.Body().SetStringVal(message)
+    if jsonLogBody {
+        setBodyJsonOrString(logEntry, message)
+    } else {
+        logEntry.Body().SetStringVal(message)
+    }
+    if severity == pdata.SeverityNumberUNDEFINED {
+        severity, severityText = rb.defaultSeverity, rb.defaultSeverityText
+    }
+    if severity != pdata.SeverityNumberUNDEFINED {
+        logEntry.SetSeverityNumber(severity)
+        logEntry.SetSeverityText(severityText)
+    }
     if region != "" {
         logEntry.Attributes().UpsertString(semconv.AttributeCloudRegion, region)
     } else if rb.parsedRegion != "" {
@@ -281,7 +435,57 @@ func (rb *otlpRequestBuilder) AddLogEntry(itemId string, timestamp int64, messag
             }
         }
     }
-    
+
+    builder = rb
+    return
+}
+
+// setBodyJsonOrString sets a JSON object message as a map body, any other message is set as a string body
+func setBodyJsonOrString(logEntry pdata.LogRecord, message string) {
+    var object map[string]interface{}
+    decoder := json.NewDecoder(strings.NewReader(message))
+    decoder.UseNumber()
+    if err := decoder.Decode(&object); err != nil || object == nil || decoder.More() {
+        logEntry.Body().SetStringVal(message)
+        return
+    }
+
+    newJsonAttributeValue(object).CopyTo(logEntry.Body())
+}
+
+func newJsonAttributeValue(value interface{}) (result pdata.AttributeValue) {
+    switch v := value.(type) {
+    case string:
+        result = pdata.NewAttributeValueString(v)
+    case bool:
+        result = pdata.NewAttributeValueBool(v)
+    case json.Number:
+        if i, err := v.Int64(); err == nil {
+            result = pdata.NewAttributeValueInt(i)
+        } else {
+            f, _ := v.Float64()
+            result = pdata.NewAttributeValueDouble(f)
+        }
+    case map[string]interface{}:
+        result = pdata.NewAttributeValueMap()
+        for key, item := range v {
+            result.MapVal().Insert(key, newJsonAttributeValue(item))
+        }
+    case []interface{}:
+        result = pdata.NewAttributeValueArray()
+        for _, item := range v {
+            newJsonAttributeValue(item).CopyTo(result.SliceVal().AppendEmpty())
+        }
+    default:
+        result = pdata.NewAttributeValueEmpty()
+    }
+    return
+}
+
+// SetDefaultSeverity sets the severity applied to all subsequently added log entries
+func (rb *otlpRequestBuilder) SetDefaultSeverity(severity pdata.SeverityNumber, severityText string) (builder OtlpRequestBuilder) {
+    rb.defaultSeverity = severity
+    rb.defaultSeverityText = severityText
     builder = rb
     return
 }
@@ -292,4 +496,42 @@ func (rb *otlpRequestBuilder) GetLogs() (logs pdata.Logs) {
     attrs.InsertString(semconv.AttributeCloudProvider, semconv.AttributeCloudProviderAWS)
 
     return
-}
\ No newline at end of file
+}
+
+// Validate checks that the resource attributes required by SolarWinds are set
+func (rb *otlpRequestBuilder) Validate() (err error) {
+    attrs := rb.resLogs.Resource().Attributes()
+    for _, key := range []string{semconv.AttributeCloudAccountID, semconv.AttributeAWSLogGroupNames, semconv.AttributeAWSLogStreamNames} {
+        value, exists := attrs.Get(key)
+        if !exists || value.StringVal() == "" {
+            err = fmt.Errorf("required resource attribute %s is not set", key)
+            return
+        }
+    }
+    return
+}
+
+// GetLogCount returns number of log entries accumulated by the builder
+func (rb *otlpRequestBuilder) GetLogCount() (count int) {
+    for i := 0; i < rb.instrLogsSlice.Len(); i++ {
+        count += rb.instrLogsSlice.At(i).Logs().Len()
+    }
+    return
+}
+
+func (rb *otlpRequestBuilder) HasLogEntries() (bool) {
+    return rb.instrLogsSlice.Len() > 0 && rb.instrLogs.Logs().Len() > 0
+}
+
+// NextBatch returns a new builder with the same resource attributes and no log entries
+func (rb *otlpRequestBuilder) NextBatch() (builder OtlpRequestBuilder) {
+    next := NewOtlpRequestBuilder().(*otlpRequestBuilder)
+    rb.resLogs.Resource().CopyTo(next.resLogs.Resource())
+    next.hostId = rb.hostId
+    next.parsedRegion = rb.parsedRegion
+    next.parsedHostId = rb.parsedHostId
+    next.defaultSeverity = rb.defaultSeverity
+    next.defaultSeverityText = rb.defaultSeverityText
+    builder = next
+    return
+}
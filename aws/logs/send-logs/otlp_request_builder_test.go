@@ -16,11 +16,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
 	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
 )
 
@@ -140,4 +143,199 @@ func TestOltpRequestBuilder(t *testing.T) {
         t.Logf(matches[i])
         //t.Fail()
     })
-}
\ No newline at end of file
+}
+
+func TestOtlpRequestBuilderSeverity(t *testing.T) {
+    rb := NewOtlpRequestBuilder()
+
+    rb.AddLogEntry("no severity", time.Now().UnixMilli(), "test body", "")
+    rb.SetDefaultSeverity(pdata.SeverityNumberINFO, "INFO")
+    rb.AddLogEntry("default severity", time.Now().UnixMilli(), "test body", "")
+    rb.AddLogEntryWithSeverity("overridden severity", time.Now().UnixMilli(), "test body", "", pdata.SeverityNumberERROR, "ERROR")
+    rb.AddLogEntryWithSeverity("undefined severity", time.Now().UnixMilli(), "test body", "", pdata.SeverityNumberUNDEFINED, "")
+
+    logEntries := rb.GetLogs().ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+    assert.Equal(t, 4, logEntries.Len())
+
+    assert.Equal(t, pdata.SeverityNumberUNDEFINED, logEntries.At(0).SeverityNumber())
+    assert.Equal(t, "", logEntries.At(0).SeverityText())
+    assert.Equal(t, pdata.SeverityNumberINFO, logEntries.At(1).SeverityNumber())
+    assert.Equal(t, "INFO", logEntries.At(1).SeverityText())
+    assert.Equal(t, pdata.SeverityNumberERROR, logEntries.At(2).SeverityNumber())
+    assert.Equal(t, "ERROR", logEntries.At(2).SeverityText())
+    assert.Equal(t, pdata.SeverityNumberINFO, logEntries.At(3).SeverityNumber())
+    assert.Equal(t, "INFO", logEntries.At(3).SeverityText())
+}
+
+func TestOtlpRequestBuilder_GetLogCount(t *testing.T) {
+    rb := NewOtlpRequestBuilder().
+        SetCloudAccount("test account").
+        SetLogGroup("test group").
+        SetLogStream("i-12345-test")
+
+    assert.Equal(t, 0, rb.GetLogCount())
+
+    rb.AddLogEntry("1", time.Now().UnixMilli(), "test body", "")
+    assert.Equal(t, 1, rb.GetLogCount())
+
+    rb.AddLogEntry("2", time.Now().UnixMilli(), "test body", "").
+        AddLogEntry("3", time.Now().UnixMilli(), "test body", "")
+    assert.Equal(t, 3, rb.GetLogCount())
+
+    t.Run("Next batch keeps resource attributes and has no log entries", func(t *testing.T) {
+        next := rb.NextBatch()
+        assert.Equal(t, 0, next.GetLogCount())
+        assert.True(t, next.MatchHostId("i-12345-test"))
+        assert.Equal(t,
+            rb.GetLogs().ResourceLogs().At(0).Resource().Attributes().AsRaw(),
+            next.GetLogs().ResourceLogs().At(0).Resource().Attributes().AsRaw())
+        assert.Equal(t, 3, rb.GetLogCount())
+    })
+}
+
+func TestOtlpRequestBuilder_SetLambdaInvocationContext(t *testing.T) {
+    functionArn := "arn:aws:lambda:us-east-1:123456789012:function:send-logs:1"
+    ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+        AwsRequestID:       "c6af9ac6-7b61-11e6-9a41-93e8deadbeef",
+        InvokedFunctionArn: functionArn,
+    })
+
+    attrs := NewOtlpRequestBuilder().
+        SetLambdaInvocationContext(ctx).
+        GetLogs().ResourceLogs().At(0).Resource().Attributes()
+
+    invocationId, exists := attrs.Get("faas.invocation_id")
+    assert.True(t, exists)
+    assert.Equal(t, "c6af9ac6-7b61-11e6-9a41-93e8deadbeef", invocationId.StringVal())
+    instance, exists := attrs.Get(semconv.AttributeFaaSInstance)
+    assert.True(t, exists)
+    assert.Equal(t, functionArn, instance.StringVal())
+
+    t.Run("No Lambda context", func(t *testing.T) {
+        attrs := NewOtlpRequestBuilder().
+            SetLambdaInvocationContext(context.Background()).
+            GetLogs().ResourceLogs().At(0).Resource().Attributes()
+
+        _, exists := attrs.Get("faas.invocation_id")
+        assert.False(t, exists)
+        _, exists = attrs.Get(semconv.AttributeFaaSInstance)
+        assert.False(t, exists)
+    })
+}
+
+func TestOtlpRequestBuilder_Validate(t *testing.T) {
+    testCases := []struct {
+        name string
+        account string
+        logGroup string
+        logStream string
+        missing string
+    }{
+        { name: "All attributes set", account: "test account", logGroup: "test group", logStream: "test stream" },
+        { name: "Missing cloud account", logGroup: "test group", logStream: "test stream", missing: semconv.AttributeCloudAccountID },
+        { name: "Missing log group", account: "test account", logStream: "test stream", missing: semconv.AttributeAWSLogGroupNames },
+        { name: "Missing log stream", account: "test account", logGroup: "test group", missing: semconv.AttributeAWSLogStreamNames },
+    }
+
+    for _, tc := range testCases {
+        t.Run(tc.name, func(t *testing.T) {
+            rb := NewOtlpRequestBuilder()
+            if tc.account != "" {
+                rb.SetCloudAccount(tc.account)
+            }
+            if tc.logGroup != "" {
+                rb.SetLogGroup(tc.logGroup)
+            }
+            if tc.logStream != "" {
+                rb.SetLogStream(tc.logStream)
+            }
+
+            err := rb.Validate()
+            if tc.missing == "" {
+                assert.Nil(t, err)
+            } else {
+                assert.Error(t, err)
+                assert.Contains(t, err.Error(), tc.missing)
+            }
+        })
+    }
+
+    t.Run("Empty value", func(t *testing.T) {
+        rb := NewOtlpRequestBuilder().
+            SetCloudAccount("").
+            SetLogGroup("test group").
+            SetLogStream("test stream")
+        assert.Error(t, rb.Validate())
+    })
+}
+
+func TestOtlpRequestBuilder_SetK8sClusterName(t *testing.T) {
+    rb := NewOtlpRequestBuilder().
+        SetK8sClusterName("production")
+
+    val, ok := rb.GetLogs().ResourceLogs().At(0).Resource().Attributes().Get(semconv.AttributeK8SClusterName)
+    assert.True(t, ok)
+    assert.Equal(t, "production", val.StringVal())
+}
+
+func TestOtlpRequestBuilder_BulkSetResourceAttributes(t *testing.T) {
+    rb := NewOtlpRequestBuilder().
+        SetCloudAccount("test account").
+        BulkSetResourceAttributes(map[string]string{
+            semconv.AttributeFaaSName: "send-logs",
+            "aws.lambda.runtime": "AWS_Lambda_provided.al2023",
+        })
+
+    attrs := rb.GetLogs().ResourceLogs().At(0).Resource().Attributes()
+    assert.Equal(t, 4, attrs.Len())
+    assertLogRecordHasAttribute(t, attrs, semconv.AttributeFaaSName, "send-logs")
+    assertLogRecordHasAttribute(t, attrs, "aws.lambda.runtime", "AWS_Lambda_provided.al2023")
+    assertLogRecordHasAttribute(t, attrs, semconv.AttributeCloudAccountID, "test account")
+}
+
+func TestOtlpRequestBuilder_HasLogEntries_EmptyBuilder(t *testing.T) {
+    rb := NewOtlpRequestBuilder().
+        SetCloudAccount("test account").
+        SetLogGroup("test group").
+        SetLogStream("test stream")
+
+    assert.False(t, rb.HasLogEntries())
+    assert.False(t, rb.NextBatch().HasLogEntries())
+}
+
+func TestOtlpRequestBuilder_HasLogEntries_WithEntry(t *testing.T) {
+    rb := NewOtlpRequestBuilder().
+        SetCloudAccount("test account").
+        SetLogGroup("test group").
+        SetLogStream("test stream").
+        AddLogEntry("1", time.Now().UnixMilli(), "test body", "")
+
+    assert.True(t, rb.HasLogEntries())
+    assert.False(t, rb.NextBatch().HasLogEntries())
+}
+
+func TestSetBodyJsonOrString(t *testing.T) {
+    logEntry := pdata.NewLogRecord()
+    setBodyJsonOrString(logEntry, `{"level":"info","status":200,"latency":0.25,"tags":["a","b"],"request":{"method":"GET"}}`)
+    assert.Equal(t, pdata.AttributeValueTypeMap, logEntry.Body().Type())
+
+    body := logEntry.Body().MapVal()
+    level, _ := body.Get("level")
+    assert.Equal(t, "info", level.StringVal())
+    status, _ := body.Get("status")
+    assert.Equal(t, int64(200), status.IntVal())
+    latency, _ := body.Get("latency")
+    assert.Equal(t, 0.25, latency.DoubleVal())
+    tags, _ := body.Get("tags")
+    assert.Equal(t, 2, tags.SliceVal().Len())
+    request, _ := body.Get("request")
+    method, _ := request.MapVal().Get("method")
+    assert.Equal(t, "GET", method.StringVal())
+
+    for _, message := range []string{`{"level":"info"`, `["a","b"]`, `plain text message`} {
+        logEntry = pdata.NewLogRecord()
+        setBodyJsonOrString(logEntry, message)
+        assert.Equal(t, pdata.AttributeValueTypeString, logEntry.Body().Type())
+        assert.Equal(t, message, logEntry.Body().StringVal())
+    }
+}
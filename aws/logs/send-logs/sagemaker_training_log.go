@@ -0,0 +1,122 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const sageMakerLogGroupPatternVar = "SAGEMAKER_LOG_GROUP_PATTERN"
+
+var (
+	sageMakerLogGroups        = newLogGroupFilter(sageMakerLogGroupPatternVar, "/aws/sagemaker/TrainingJobs")
+	detectSageMakerLogStream  = regexp.MustCompile(`^(?P<JobName>[^/]+)/(?P<Algo>algo-\d+-[^/]+)$`)
+	sageMakerJobNameIndex     = detectSageMakerLogStream.SubexpIndex("JobName")
+	sageMakerStreamAlgoIndex  = detectSageMakerLogStream.SubexpIndex("Algo")
+	detectSageMakerLine       = regexp.MustCompile(`^\[(?P<Timestamp>[^\]]+)\]\s+\[(?P<Algo>[^\]]+)\]\s+\[(?P<Level>INFO|WARN|WARNING|ERROR|DEBUG)\]\s?(?P<Message>.*)$`)
+	sageMakerTimestampIndex   = detectSageMakerLine.SubexpIndex("Timestamp")
+	sageMakerAlgoIndex        = detectSageMakerLine.SubexpIndex("Algo")
+	sageMakerLevelIndex       = detectSageMakerLine.SubexpIndex("Level")
+	sageMakerMessageIndex     = detectSageMakerLine.SubexpIndex("Message")
+	sageMakerTimestampLayouts = []string{"2006-01-02 15:04:05.000", "2006-01-02 15:04:05", time.RFC3339Nano}
+)
+
+// SageMaker training job log, the log stream is named <job-name>/<algo container>
+type sageMakerTrainingLog struct {
+	jobName string
+	algo    string
+}
+
+func newSageMakerLogSource(logGroup, logStream string) (source *sageMakerTrainingLog, ok bool) {
+	if !sageMakerLogGroups.match(logGroup) {
+		return
+	}
+
+	matches := detectSageMakerLogStream.FindStringSubmatch(logStream)
+	if matches == nil {
+		return
+	}
+
+	source = &sageMakerTrainingLog{
+		jobName: matches[sageMakerJobNameIndex],
+		algo:    matches[sageMakerStreamAlgoIndex],
+	}
+	ok = true
+	return
+}
+
+func (src *sageMakerTrainingLog) setResourceAttributes(reqBuilder OtlpRequestBuilder) OtlpRequestBuilder {
+	return reqBuilder.
+		SetSageMakerTrainingJobName(src.jobName).
+		SetServiceName("sagemaker").
+		SetKubernetesPodName(src.algo)
+}
+
+func (src *sageMakerTrainingLog) addLogEntry(reqBuilder OtlpRequestBuilder, itemId string, timestamp int64, message string) OtlpRequestBuilder {
+	_, _, level, _, err := parseSageMakerLine(message)
+	if err != nil {
+		return reqBuilder.AddLogEntry(itemId, timestamp, message, lambdaRegion)
+	}
+
+	severity, severityText := sageMakerSeverity(level)
+	return reqBuilder.AddLogEntryWithSeverity(itemId, timestamp, message, lambdaRegion, severity, severityText)
+}
+
+// parseSageMakerLine parses a "[timestamp] [algo-1-abc12] [LEVEL] message" training log line
+func parseSageMakerLine(line string) (timestamp time.Time, algo, level, message string, err error) {
+	matches := detectSageMakerLine.FindStringSubmatch(line)
+	if matches == nil {
+		err = fmt.Errorf("not a SageMaker training log line")
+		return
+	}
+
+	for _, layout := range sageMakerTimestampLayouts {
+		if timestamp, err = time.Parse(layout, matches[sageMakerTimestampIndex]); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	algo = matches[sageMakerAlgoIndex]
+	level = matches[sageMakerLevelIndex]
+	message = matches[sageMakerMessageIndex]
+	return
+}
+
+func sageMakerSeverity(level string) (severity pdata.SeverityNumber, severityText string) {
+	switch strings.ToUpper(level) {
+	case "ERROR":
+		severity = pdata.SeverityNumberERROR
+		severityText = "ERROR"
+	case "WARN", "WARNING":
+		severity = pdata.SeverityNumberWARN
+		severityText = "WARN"
+	case "INFO":
+		severity = pdata.SeverityNumberINFO
+		severityText = "INFO"
+	case "DEBUG":
+		severity = pdata.SeverityNumberDEBUG
+		severityText = "DEBUG"
+	}
+	return
+}
@@ -0,0 +1,101 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestParseSageMakerLine(t *testing.T) {
+	testCases := []struct {
+		line      string
+		ok        bool
+		timestamp time.Time
+		algo      string
+		level     string
+		message   string
+	}{
+		{"[2024-01-15 10:30:45.123] [algo-1-abc12] [INFO] Starting training", true, time.Date(2024, 1, 15, 10, 30, 45, 123000000, time.UTC), "algo-1-abc12", "INFO", "Starting training"},
+		{"[2024-01-15T10:30:45Z] [algo-2-xyz89] [ERROR] CUDA out of memory", true, time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC), "algo-2-xyz89", "ERROR", "CUDA out of memory"},
+		{"[yesterday] [algo-1-abc12] [INFO] Starting training", false, time.Time{}, "", "", ""},
+		{"Epoch 1/10 - loss: 0.4312", false, time.Time{}, "", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			timestamp, algo, level, message, err := parseSageMakerLine(tc.line)
+			assert.Equal(t, tc.ok, err == nil)
+			assert.True(t, tc.timestamp.Equal(timestamp))
+			assert.Equal(t, tc.algo, algo)
+			assert.Equal(t, tc.level, level)
+			assert.Equal(t, tc.message, message)
+		})
+	}
+}
+
+func TestSageMakerLogSourceDetection(t *testing.T) {
+	source, ok := newSageMakerLogSource("/aws/sagemaker/TrainingJobs", "my-training-job/algo-1-1705314645")
+	assert.True(t, ok)
+	assert.Equal(t, "my-training-job", source.jobName)
+	assert.Equal(t, "algo-1-1705314645", source.algo)
+
+	_, ok = newSageMakerLogSource("/aws/sagemaker/TrainingJobs", "my-training-job")
+	assert.False(t, ok)
+
+	_, ok = newSageMakerLogSource("/aws/lambda/my-function", "my-training-job/algo-1-1705314645")
+	assert.False(t, ok)
+}
+
+func TestLogEventsTransformSageMakerTrainingLog(t *testing.T) {
+	data, err := os.ReadFile("testdata/sagemaker_training.txt")
+	assert.Nil(t, err)
+
+	logEvents := make([]events.CloudwatchLogsLogEvent, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		logEvents = append(logEvents, events.CloudwatchLogsLogEvent{
+			ID:        "1",
+			Timestamp: time.Now().Unix(),
+			Message:   line,
+		})
+	}
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws/sagemaker/TrainingJobs", "my-training-job/algo-1-abc12", logEvents, output, nil, nil)
+	logs := <-output
+
+	resourceAttributes := logs.ResourceLogs().At(0).Resource().Attributes()
+	assertLogRecordHasAttribute(t, resourceAttributes, "aws.sagemaker.training_job_name", "my-training-job")
+	assertLogRecordHasAttribute(t, resourceAttributes, "service.name", "sagemaker")
+	assertLogRecordHasAttribute(t, resourceAttributes, "k8s.pod.name", "algo-1-abc12")
+
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, len(logEvents), logRecords.Len())
+	assert.Equal(t, pdata.SeverityNumberINFO, logRecords.At(0).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberWARN, logRecords.At(1).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberERROR, logRecords.At(2).SeverityNumber())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, logRecords.At(3).SeverityNumber())
+
+	for range output {
+	}
+}
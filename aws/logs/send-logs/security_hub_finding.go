@@ -0,0 +1,134 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	securityHubLogGroupPatternVar = "SECURITY_HUB_LOG_GROUP_PATTERN"
+	securityHubEventSource        = "aws.securityhub"
+)
+
+var securityHubLogGroups = newLogGroupFilter(securityHubLogGroupPatternVar, "(?i)securityhub")
+
+type securityHubResource struct {
+	Type string `json:"Type"`
+	Id   string `json:"Id"`
+}
+
+type securityHubSeverity struct {
+	Label string `json:"Label"`
+}
+
+type securityHubCompliance struct {
+	Status string `json:"Status"`
+}
+
+// AWS Security Hub finding, forwarded as a separate log record with the finding JSON as the body
+type securityHubFinding struct {
+	Title      string                `json:"Title"`
+	ProductArn string                `json:"ProductArn"`
+	Severity   securityHubSeverity   `json:"Severity"`
+	Resources  []securityHubResource `json:"Resources"`
+	Compliance securityHubCompliance `json:"Compliance"`
+	raw        string
+}
+
+// Security Hub findings event delivered to CloudWatch Logs by EventBridge
+type securityHubEvent struct {
+	Source string `json:"source"`
+	Region string `json:"region"`
+	Detail struct {
+		Findings []securityHubFinding `json:"findings"`
+	} `json:"detail"`
+}
+
+func isSecurityHubEvent(logGroup string, jsonEvent map[string]interface{}) bool {
+	return securityHubLogGroups.match(logGroup) &&
+		testJsonPath(jsonEvent, "source", securityHubEventSource) &&
+		testJsonPath(jsonEvent, "detail.findings")
+}
+
+func (f *securityHubFinding) UnmarshalJSON(data []byte) error {
+	type finding securityHubFinding
+	if err := json.Unmarshal(data, (*finding)(f)); err != nil {
+		return err
+	}
+	f.raw = string(data)
+	return nil
+}
+
+func (evt *securityHubEvent) getInstanceId() (result string, err error) {
+	err = errors.New("Security Hub event doesn't contain EC2 Instance ID")
+	return
+}
+
+func (evt *securityHubEvent) getRegion() (result string) {
+	result = evt.Region
+	return
+}
+
+func (evt *securityHubEvent) getEventType() (result string) {
+	result = ec2Event
+	return
+}
+
+func (evt *securityHubEvent) getLogRecords() (result []iLogRecordEvent) {
+	for i := range evt.Detail.Findings {
+		result = append(result, &evt.Detail.Findings[i])
+	}
+	return
+}
+
+func (f *securityHubFinding) getBody() (result string) {
+	result = f.raw
+	return
+}
+
+func (f *securityHubFinding) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	switch f.Severity.Label {
+	case "CRITICAL":
+		severity = pdata.SeverityNumberFATAL
+	case "HIGH":
+		severity = pdata.SeverityNumberERROR
+	case "MEDIUM":
+		severity = pdata.SeverityNumberWARN
+	case "LOW", "INFORMATIONAL":
+		severity = pdata.SeverityNumberINFO
+	default:
+		return
+	}
+	severityText = f.Severity.Label
+	return
+}
+
+func (f *securityHubFinding) getAttributes() (result map[string]interface{}) {
+	result = map[string]interface{}{
+		"aws.security_hub.product_arn": f.ProductArn,
+	}
+	if len(f.Resources) > 0 {
+		result["cloud.resource_type"] = f.Resources[0].Type
+	}
+	if f.Compliance.Status != "" {
+		result["security.compliance.status"] = f.Compliance.Status
+	}
+	return
+}
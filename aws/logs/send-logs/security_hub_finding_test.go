@@ -0,0 +1,124 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+)
+
+func TestSecurityHubFindingSeverity(t *testing.T) {
+	testCases := []struct {
+		label    string
+		severity pdata.SeverityNumber
+	}{
+		{"CRITICAL", pdata.SeverityNumberFATAL},
+		{"HIGH", pdata.SeverityNumberERROR},
+		{"MEDIUM", pdata.SeverityNumberWARN},
+		{"LOW", pdata.SeverityNumberINFO},
+		{"INFORMATIONAL", pdata.SeverityNumberINFO},
+		{"", pdata.SeverityNumberUNDEFINED},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			finding := securityHubFinding{Severity: securityHubSeverity{Label: tc.label}}
+			severity, _ := finding.getSeverity()
+			assert.Equal(t, tc.severity, severity)
+		})
+	}
+}
+
+func TestSecurityHubEventDetection(t *testing.T) {
+	message, err := os.ReadFile("testdata/security_hub_findings.json")
+	assert.Nil(t, err)
+
+	ok, result := parseMessage("/aws/events/securityhub", string(message))
+	assert.True(t, ok)
+	assert.IsType(t, &securityHubEvent{}, result)
+
+	ok, _ = parseMessage("/aws/lambda/my-function", string(message))
+	assert.False(t, ok)
+}
+
+func TestLogEventsTransformSecurityHubFindings(t *testing.T) {
+	message, err := os.ReadFile("testdata/security_hub_findings.json")
+	assert.Nil(t, err)
+
+	logEvents := []events.CloudwatchLogsLogEvent{
+		{
+			ID:        "1",
+			Timestamp: time.Now().UnixMilli(),
+			Message:   string(message),
+		},
+	}
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws/events/securityhub", "stream", logEvents, output, nil, nil)
+
+	logs := <-output
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, 2, logRecords.Len())
+
+	assert.Equal(t, pdata.SeverityNumberERROR, logRecords.At(0).SeverityNumber())
+	assert.Equal(t, "HIGH", logRecords.At(0).SeverityText())
+	assert.True(t, strings.HasPrefix(strings.TrimSpace(logRecords.At(0).Body().StringVal()), "{"))
+	assert.Contains(t, logRecords.At(0).Body().StringVal(), "S3.8 S3 Block Public Access")
+	attributes := logRecords.At(0).Attributes()
+	assertLogRecordHasAttribute(t, attributes, "aws.security_hub.product_arn", "arn:aws:securityhub:us-east-1::product/aws/securityhub")
+	assertLogRecordHasAttribute(t, attributes, "cloud.resource_type", "AwsS3Bucket")
+	assertLogRecordHasAttribute(t, attributes, "security.compliance.status", "FAILED")
+	assertLogRecordHasAttribute(t, attributes, semconv.AttributeCloudRegion, "us-east-1")
+
+	assert.Equal(t, pdata.SeverityNumberINFO, logRecords.At(1).SeverityNumber())
+	assert.Contains(t, logRecords.At(1).Body().StringVal(), "Unusual API call observed")
+	assertLogRecordHasAttribute(t, logRecords.At(1).Attributes(), "cloud.resource_type", "AwsIamAccessKey")
+	assertLogRecordDoNotHaveAttribute(t, logRecords.At(1).Attributes(), "security.compliance.status")
+
+	for range output {
+	}
+}
+
+func TestLogEventsTransformSecurityHubEmptyFindings(t *testing.T) {
+	message := `{"source": "aws.securityhub", "region": "us-east-1", "detail": {"findings": []}}`
+	logEvents := []events.CloudwatchLogsLogEvent{
+		{
+			ID:        "1",
+			Timestamp: time.Now().UnixMilli(),
+			Message:   message,
+		},
+	}
+
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws/events/securityhub", "stream", logEvents, output, nil, nil)
+
+	logs := <-output
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, 1, logRecords.Len())
+	assert.Equal(t, message, logRecords.At(0).Body().StringVal())
+	assertLogRecordHasAttribute(t, logRecords.At(0).Attributes(), semconv.AttributeCloudRegion, "us-east-1")
+
+	for range output {
+	}
+}
@@ -0,0 +1,50 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+const (
+	timeoutWarnThresholdVar       = "TIMEOUT_WARN_THRESHOLD_MS"
+	defaultTimeoutWarnThresholdMs = 5000
+)
+
+var timeoutWarnThreshold = time.Duration(getEnvInt(timeoutWarnThresholdVar, defaultTimeoutWarnThresholdMs)) * time.Millisecond
+
+// log a warning when the invocation gets close to the Lambda timeout, the returned function cancels the warning
+func startTimeoutWarning(ctx context.Context, logGroup string, eventsRemaining func() int) (stop func() bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok || timeoutWarnThreshold <= 0 {
+		return func() bool { return false }
+	}
+
+	requestId := ""
+	if lambdaContext, ok := lambdacontext.FromContext(ctx); ok {
+		requestId = lambdaContext.AwsRequestID
+	}
+
+	timer := time.AfterFunc(time.Until(deadline)-timeoutWarnThreshold, func() {
+		appLogger.Warn(fmt.Sprintf("Function execution is about to time out: remaining_ms=%d log_group=%s events_remaining=%d request_id=%s",
+			time.Until(deadline).Milliseconds(), logGroup, eventsRemaining(), requestId))
+	})
+	return timer.Stop
+}
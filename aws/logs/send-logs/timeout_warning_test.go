@@ -0,0 +1,128 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+type channelLogger struct {
+	messages chan string
+}
+
+func (l channelLogger) Info(v ...interface{}) {
+	l.messages <- fmt.Sprint(v...)
+}
+
+func (l channelLogger) Warn(v ...interface{}) {
+	l.messages <- fmt.Sprint(v...)
+}
+
+func (l channelLogger) Error(v ...interface{}) {
+	l.messages <- fmt.Sprint(v...)
+}
+
+func (l channelLogger) Fatal(v ...interface{}) {
+	l.messages <- fmt.Sprint(v...)
+}
+
+// capture messages logged by the function until the end of the test
+func captureLogMessages(t *testing.T) chan string {
+	messages := make(chan string, 1)
+	originalLogger := appLogger
+	appLogger = channelLogger{messages}
+	t.Cleanup(func() {
+		appLogger = originalLogger
+	})
+	return messages
+}
+
+func setTimeoutWarningTest(t *testing.T, threshold time.Duration) chan string {
+	originalThreshold := timeoutWarnThreshold
+	timeoutWarnThreshold = threshold
+	t.Cleanup(func() {
+		timeoutWarnThreshold = originalThreshold
+	})
+	return captureLogMessages(t)
+}
+
+func TestTimeoutWarning_Fires(t *testing.T) {
+	messages := setTimeoutWarningTest(t, 50*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+
+	stop := startTimeoutWarning(ctx, "/aws/test/group", func() int { return 7 })
+	defer stop()
+
+	select {
+	case message := <-messages:
+		assert.Contains(t, message, "remaining_ms=")
+		assert.Contains(t, message, "log_group=/aws/test/group")
+		assert.Contains(t, message, "events_remaining=7")
+	case <-time.After(time.Second):
+		t.Fatal("timeout warning was not logged")
+	}
+}
+
+func TestTimeoutWarning_Cancelled(t *testing.T) {
+	messages := setTimeoutWarningTest(t, 50*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	stop := startTimeoutWarning(ctx, "/aws/test/group", func() int { return 0 })
+	assert.True(t, stop())
+
+	select {
+	case message := <-messages:
+		t.Fatal("unexpected timeout warning: ", message)
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestTimeoutWarning_NoDeadline(t *testing.T) {
+	setTimeoutWarningTest(t, 50*time.Millisecond)
+	stop := startTimeoutWarning(context.Background(), "/aws/test/group", func() int { return 0 })
+	assert.False(t, stop())
+}
+
+func TestTransformLogEventsCountsProcessedEvents(t *testing.T) {
+	message, err := os.ReadFile("testdata/security_hub_findings.json")
+	assert.Nil(t, err)
+	logEvents := []events.CloudwatchLogsLogEvent{
+		{ID: "1", Timestamp: time.Now().UnixMilli(), Message: string(message)},
+		{ID: "2", Timestamp: time.Now().UnixMilli(), Message: `{"source": "aws.securityhub", "detail": {"findings": []}}`},
+	}
+
+	var processedEvents int64
+	output := make(chan pdata.Logs)
+	go transformLogEvents(context.Background(), "test account", "/aws/events/securityhub", "stream", logEvents, output, nil, &processedEvents)
+
+	logRecords := 0
+	for logs := range output {
+		logRecords += logs.LogRecordCount()
+	}
+	assert.Equal(t, 3, logRecords)
+	assert.Equal(t, int64(len(logEvents)), atomic.LoadInt64(&processedEvents))
+}
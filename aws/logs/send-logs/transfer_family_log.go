@@ -0,0 +1,102 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	transferLogGroupPatternVar         = "TRANSFER_LOG_GROUP_PATTERN"
+	transferBytesTransferredMetricName = "aws.transfer.bytes_transferred"
+	transferStatusFailure              = "FAILURE"
+)
+
+var transferLogGroups = newLogGroupFilter(transferLogGroupPatternVar, "/aws/transfer/")
+
+// AWS Transfer Family (SFTP/FTPS) server log entry, type is one of LOGON, FILE_UPLOAD or FILE_DOWNLOAD.
+// bytesTransferred is only present on file transfers.
+type transferFamilyLog struct {
+	Type             string `json:"type"`
+	Username         string `json:"username"`
+	Status           string `json:"status"`
+	ServerId         string `json:"serverId"`
+	ClientIp         string `json:"clientIp"`
+	FileName         string `json:"fileName"`
+	BytesTransferred int64  `json:"bytesTransferred"`
+}
+
+func isTransferFamilyLog(logGroup string, jsonEvent map[string]interface{}) bool {
+	return transferLogGroups.match(logGroup) &&
+		testJsonPath(jsonEvent, "serverId") &&
+		testJsonPath(jsonEvent, "type")
+}
+
+func (evt *transferFamilyLog) getInstanceId() (result string, err error) {
+	err = errors.New("Transfer Family log doesn't contain EC2 Instance ID")
+	return
+}
+
+func (evt *transferFamilyLog) getRegion() (result string) {
+	result = lambdaRegion
+	return
+}
+
+func (evt *transferFamilyLog) getEventType() (result string) {
+	result = ec2Event
+	return
+}
+
+func (evt *transferFamilyLog) getBody() (result string) {
+	return
+}
+
+func (evt *transferFamilyLog) getSeverity() (severity pdata.SeverityNumber, severityText string) {
+	if evt.Status == transferStatusFailure {
+		severity = pdata.SeverityNumberERROR
+		severityText = "ERROR"
+	}
+	return
+}
+
+func (evt *transferFamilyLog) getAttributes() (result map[string]interface{}) {
+	result = map[string]interface{}{
+		"ftp.server_id": evt.ServerId,
+		"ftp.operation": evt.Type,
+	}
+	if evt.Username != "" {
+		result["enduser.id"] = evt.Username
+	}
+	if evt.ClientIp != "" {
+		result["net.peer.ip"] = evt.ClientIp
+	}
+	if evt.FileName != "" {
+		result["file.path"] = evt.FileName
+	}
+	return
+}
+
+func (evt *transferFamilyLog) addMetrics(metricsBuilder OtlpMetricsBuilder, timestamp int64) {
+	if evt.BytesTransferred <= 0 {
+		return
+	}
+	metricsBuilder.AddGauge(transferBytesTransferredMetricName, "By", timestamp, float64(evt.BytesTransferred), map[string]interface{}{
+		"ftp.server_id": evt.ServerId,
+		"ftp.operation": evt.Type,
+	})
+}
@@ -0,0 +1,111 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestTransferFamilyLogGroupFilter(t *testing.T) {
+	message, err := os.ReadFile("testdata/transfer_family_log.json")
+	assert.Nil(t, err)
+
+	ok, result := parseMessage("/aws/transfer/s-01234567890abcdef", string(message))
+	assert.True(t, ok)
+	assert.IsType(t, &transferFamilyLog{}, result)
+
+	ok, _ = parseMessage("/aws/lambda/test", string(message))
+	assert.False(t, ok)
+
+	defaultTransferLogGroups := transferLogGroups
+	defer func() { transferLogGroups = defaultTransferLogGroups }()
+	os.Setenv(transferLogGroupPatternVar, "^/sftp/")
+	defer os.Unsetenv(transferLogGroupPatternVar)
+	transferLogGroups = newLogGroupFilter(transferLogGroupPatternVar)
+
+	ok, _ = parseMessage("/sftp/partners", string(message))
+	assert.True(t, ok)
+
+	ok, _ = parseMessage("/aws/transfer/s-01234567890abcdef", string(message))
+	assert.False(t, ok)
+}
+
+func TestTransferFamilyLogonDetection(t *testing.T) {
+	for _, message := range []string{
+		`{"type": "LOGON", "username": "partner-acme", "status": "SUCCESS", "serverId": "s-01234567890abcdef", "clientIp": "203.0.113.10"}`,
+		`{"type": "LOGON", "username": "partner-acme", "status": "FAILURE", "serverId": "s-01234567890abcdef", "clientIp": "203.0.113.10"}`,
+	} {
+		ok, result := parseMessage("/aws/transfer/s-01234567890abcdef", message)
+		assert.True(t, ok)
+		assert.IsType(t, &transferFamilyLog{}, result)
+	}
+}
+
+func TestLogEventsTransformTransferFamilyLog(t *testing.T) {
+	message, err := os.ReadFile("testdata/transfer_family_log.json")
+	assert.Nil(t, err)
+	uploadMessage := string(message)
+	failedLogonMessage := `{"type": "LOGON", "username": "partner-acme", "status": "FAILURE", "serverId": "s-01234567890abcdef", "clientIp": "203.0.113.10"}`
+
+	logEvents := []events.CloudwatchLogsLogEvent{
+		{
+			ID:        "1",
+			Timestamp: time.Now().UnixMilli(),
+			Message:   uploadMessage,
+		},
+		{
+			ID:        "2",
+			Timestamp: time.Now().UnixMilli(),
+			Message:   failedLogonMessage,
+		},
+	}
+
+	output := make(chan pdata.Logs)
+	metricsOutput := make(chan pdata.Metrics)
+	go transformLogEvents(context.Background(), "test account", "/aws/transfer/s-01234567890abcdef", "partner-acme.1234", logEvents, output, metricsOutput, nil)
+
+	logs := <-output
+	logRecords := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, 2, logRecords.Len())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, logRecords.At(0).SeverityNumber())
+	attributes := logRecords.At(0).Attributes()
+	assertLogRecordHasAttribute(t, attributes, "ftp.server_id", "s-01234567890abcdef")
+	assertLogRecordHasAttribute(t, attributes, "ftp.operation", "FILE_UPLOAD")
+	assertLogRecordHasAttribute(t, attributes, "enduser.id", "partner-acme")
+	assertLogRecordHasAttribute(t, attributes, "net.peer.ip", "203.0.113.10")
+	assertLogRecordHasAttribute(t, attributes, "file.path", "/uploads/invoices/2024-01.csv")
+	assert.Equal(t, pdata.SeverityNumberERROR, logRecords.At(1).SeverityNumber())
+	assertLogRecordHasAttribute(t, logRecords.At(1).Attributes(), "ftp.operation", "LOGON")
+	for range output {
+	}
+
+	metrics := <-metricsOutput
+	assert.Equal(t, 1, metrics.MetricCount())
+	metric := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, transferBytesTransferredMetricName, metric.Name())
+	assert.Equal(t, float64(52428), metric.Gauge().DataPoints().At(0).DoubleVal())
+	assertLogRecordHasAttribute(t, metric.Gauge().DataPoints().At(0).Attributes(), "ftp.operation", "FILE_UPLOAD")
+
+	_, open := <-metricsOutput
+	assert.False(t, open)
+}
@@ -0,0 +1,63 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+const fargateProfileLabel = "eks.amazonaws.com/fargate-profile"
+
+// read integer environment variable, default value is returned when the variable is not set or invalid
+func getEnvInt(name string, defaultValue int64) int64 {
+	value, exists := os.LookupEnv(name)
+	if !exists || value == "" {
+		return defaultValue
+	}
+
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		appLogger.Error("Invalid ", name, " value, using default ", defaultValue, ": ", err.Error())
+		return defaultValue
+	}
+	return result
+}
+
+// detect Fargate nodes by the fargate- host name prefix. Newer Fargate node names don't carry the prefix,
+// pods on those nodes are recognized by the Fargate profile label EKS adds to every pod it schedules on Fargate
+func isFargateNode(host string, labels map[string]string) bool {
+	matches := detectInstanceNameAndRegion.FindStringSubmatch(host)
+	if matches != nil && fargateParamIndex < len(matches) && matches[fargateParamIndex] != "" {
+		return true
+	}
+
+	_, ok := labels[fargateProfileLabel]
+	return ok
+}
+
+// truncate string to at most maxLength characters without splitting multi-byte characters
+func truncateString(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+
+	runes := []rune(value)
+	if len(runes) <= maxLength {
+		return value
+	}
+	return string(runes[:maxLength])
+}
@@ -0,0 +1,74 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+)
+
+func TestIsFargateNode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		host     string
+		labels   map[string]string
+		expected bool
+	}{
+		{"Fargate prefix", "fargate-ip-192-168-103-27.us-east-1.compute.internal", nil, true},
+		{"EC2 node without domain", "ip-192-168-103-27", nil, false},
+		{"New Fargate naming with Fargate profile label", "a1b2c3d4e5f6-node", map[string]string{fargateProfileLabel: "default"}, true},
+		{"New Fargate naming without labels", "a1b2c3d4e5f6-node", nil, false},
+		{"EC2 node", "ip-192-168-103-27.us-east-1.compute.internal", nil, false},
+		{"EC2 instance id node", "i-0123456789abcdef0.us-east-1.compute.internal", nil, false},
+		{"Custom named EC2 node", "worker-01.k8s.example.com", map[string]string{"app": "php-app"}, false},
+		{"Empty host", "", nil, false},
+		{"Empty host with Fargate profile label", "", map[string]string{fargateProfileLabel: "default"}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isFargateNode(tc.host, tc.labels))
+		})
+	}
+}
+
+func TestCloudInsightsAppLogFargateEventType(t *testing.T) {
+	appLog := cloudInsightsAppLog{LogType: "application"}
+	appLog.Kubernetes.Host = "a1b2c3d4e5f6-node"
+	appLog.Kubernetes.Labels = map[string]string{fargateProfileLabel: "default"}
+	appLog.parse()
+
+	assert.Equal(t, fargateEvent, appLog.getEventType())
+	_, err := appLog.getInstanceId()
+	assert.Error(t, err)
+
+	appLog = cloudInsightsAppLog{LogType: "application"}
+	appLog.Kubernetes.Host = "ip-192-168-103-27.us-east-1.compute.internal"
+	appLog.parse()
+
+	assert.Equal(t, ec2Event, appLog.getEventType())
+	instanceId, err := appLog.getInstanceId()
+	assert.Nil(t, err)
+	assert.Equal(t, "ip-192-168-103-27", instanceId)
+	assert.Equal(t, "us-east-1", appLog.getRegion())
+
+	appLog = cloudInsightsAppLog{LogType: "application"}
+	appLog.Kubernetes.Host = "worker-01.k8s.example.com"
+	appLog.parse()
+
+	assert.Equal(t, ec2Event, appLog.getEventType())
+}
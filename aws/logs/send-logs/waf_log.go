@@ -0,0 +1,80 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"errors"
+)
+
+const (
+	wafLogGroupPatternVar = "WAF_LOG_GROUP_PATTERN"
+	wafRuleHitMetricName  = "AWS.WAF.ManagedRuleGroup.Hits"
+)
+
+// WAF requires the names of log groups receiving web ACL traffic logs to start with aws-waf-logs-
+var wafLogGroups = newLogGroupFilter(wafLogGroupPatternVar, "^aws-waf-logs-")
+
+type wafTerminatingRule struct {
+	RuleId string `json:"ruleId"`
+	Action string `json:"action"`
+}
+
+type wafRuleGroup struct {
+	RuleGroupId     string              `json:"ruleGroupId"`
+	TerminatingRule *wafTerminatingRule `json:"terminatingRule"`
+}
+
+// WAF v2 web ACL traffic log entry, only the fields used for the rule group metrics are decoded
+type wafLog struct {
+	WebAclId      string         `json:"webaclId"`
+	Action        string         `json:"action"`
+	RuleGroupList []wafRuleGroup `json:"ruleGroupList"`
+}
+
+func isWafLog(logGroup string, jsonEvent map[string]interface{}) bool {
+	return wafLogGroups.match(logGroup) &&
+		testJsonPath(jsonEvent, "webaclId") &&
+		testJsonPath(jsonEvent, "terminatingRuleId")
+}
+
+func (evt *wafLog) getInstanceId() (result string, err error) {
+	err = errors.New("WAF log doesn't contain EC2 Instance ID")
+	return
+}
+
+func (evt *wafLog) getRegion() (result string) {
+	result = lambdaRegion
+	return
+}
+
+func (evt *wafLog) getEventType() (result string) {
+	result = ec2Event
+	return
+}
+
+// one hit per rule group which terminated the request, e.g. a managed rule group blocking it
+func (evt *wafLog) addMetrics(metricsBuilder OtlpMetricsBuilder, timestamp int64) {
+	for _, ruleGroup := range evt.RuleGroupList {
+		if ruleGroup.TerminatingRule == nil || ruleGroup.TerminatingRule.RuleId == "" {
+			continue
+		}
+		metricsBuilder.AddCounter(wafRuleHitMetricName, "1", timestamp, 1, map[string]interface{}{
+			"rule_id":       ruleGroup.TerminatingRule.RuleId,
+			"rule_group_id": ruleGroup.RuleGroupId,
+			"action":        ruleGroup.TerminatingRule.Action,
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	assert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestWafLogDetection(t *testing.T) {
+	message, err := os.ReadFile("testdata/waf_log.json")
+	assert.Nil(t, err)
+
+	ok, result := parseMessage("aws-waf-logs-my-web-acl", string(message))
+	assert.True(t, ok)
+	assert.IsType(t, &wafLog{}, result)
+
+	ok, _ = parseMessage("/aws/lambda/my-function", string(message))
+	assert.False(t, ok)
+}
+
+func TestWAFLogMetrics_MultipleRuleGroupHits(t *testing.T) {
+	message, err := os.ReadFile("testdata/waf_log.json")
+	assert.Nil(t, err)
+
+	logEvents := []events.CloudwatchLogsLogEvent{{
+		ID:        "1",
+		Timestamp: time.Now().UnixMilli(),
+		Message:   string(message),
+	}}
+
+	output := make(chan pdata.Logs)
+	metricsOutput := make(chan pdata.Metrics)
+	go transformLogEvents(context.Background(), "test account", "aws-waf-logs-my-web-acl", "us-east-1_my-web-acl_0", logEvents, output, metricsOutput, nil)
+
+	logs := <-output
+	assert.Equal(t, 1, logs.LogRecordCount())
+	for range output {
+	}
+
+	metrics := <-metricsOutput
+	assert.Equal(t, 3, metrics.DataPointCount())
+	hits := make(map[string]pdata.AttributeMap)
+	metricSlice := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metricSlice.Len(); i++ {
+		assert.Equal(t, wafRuleHitMetricName, metricSlice.At(i).Name())
+		dataPoint := metricSlice.At(i).Sum().DataPoints().At(0)
+		assert.Equal(t, int64(1), dataPoint.IntVal())
+		ruleId, _ := dataPoint.Attributes().Get("rule_id")
+		hits[ruleId.StringVal()] = dataPoint.Attributes()
+	}
+	assert.Len(t, hits, 3)
+	assertLogRecordHasAttribute(t, hits["SizeRestrictions_BODY"], "rule_group_id", "AWS#AWSManagedRulesCommonRuleSet")
+	assertLogRecordHasAttribute(t, hits["SizeRestrictions_BODY"], "action", "BLOCK")
+	assertLogRecordHasAttribute(t, hits["SQLi_BODY"], "action", "COUNT")
+
+	_, open := <-metricsOutput
+	assert.False(t, open)
+}
@@ -0,0 +1,3 @@
+module azure-logs
+
+go 1.16
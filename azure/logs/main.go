@@ -0,0 +1,360 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	otelEndpointVar      = "SWI_OTEL_ENDPOINT"
+	apiKeyVar            = "SWI_API_KEY"
+	customHandlerPortVar = "FUNCTIONS_CUSTOMHANDLER_PORT"
+	defaultPort          = "8080"
+	functionRoute        = "/api/SendLogs"
+	exportTimeout        = 30 * time.Second
+	maxRequestBodyBytes  = 64 << 20
+	cloudProviderAzure   = "azure"
+	scopeName            = "azure-logs"
+)
+
+var (
+	otelEndpoint = os.Getenv(otelEndpointVar)
+	apiKey       = os.Getenv(apiKeyVar)
+	httpClient   = &http.Client{Timeout: exportTimeout}
+	appLogger    = log.New(log.Writer(), "azure-logs ", log.Lmsgprefix)
+
+	// resource provider type from the resource id mapped to the cloud.platform semantic convention value
+	cloudPlatforms = map[string]string{
+		"microsoft.compute/virtualmachines":           "azure_vm",
+		"microsoft.compute/virtualmachinescalesets":   "azure_vm",
+		"microsoft.containerservice/managedclusters":  "azure_aks",
+		"microsoft.containerinstance/containergroups": "azure_container_instances",
+		"microsoft.web/sites":                         "azure_app_service",
+	}
+)
+
+// OTLP/HTTP JSON encoding of the logs export request
+type anyValue map[string]interface{}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type logRecord struct {
+	TimeUnixNano   string     `json:"timeUnixNano,omitempty"`
+	SeverityNumber int        `json:"severityNumber,omitempty"`
+	SeverityText   string     `json:"severityText,omitempty"`
+	Body           anyValue   `json:"body"`
+	Attributes     []keyValue `json:"attributes,omitempty"`
+}
+
+type scopeLogs struct {
+	Scope      map[string]string `json:"scope"`
+	LogRecords []logRecord       `json:"logRecords"`
+}
+
+type resourceLogs struct {
+	Resource  map[string][]keyValue `json:"resource"`
+	ScopeLogs []scopeLogs           `json:"scopeLogs"`
+}
+
+type exportLogsRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+func main() {
+	if otelEndpoint == "" || apiKey == "" {
+		appLogger.Fatalf("Function execution parameters are not configured. Please set %s and %s environment variables", otelEndpointVar, apiKeyVar)
+	}
+
+	port, exists := os.LookupEnv(customHandlerPortVar)
+	if !exists {
+		port = defaultPort
+	}
+
+	http.HandleFunc(functionRoute, handleLogs)
+	appLogger.Println("Listening on port", port)
+	appLogger.Fatal(http.ListenAndServe(":"+port, nil))
+}
+
+// handleLogs is the HTTP trigger receiving Log Analytics query results or Event Hub diagnostic log messages
+func handleLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodyBytes))
+	if err != nil {
+		appLogger.Println("ERROR While reading request body:", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records, err := decodeRecords(body)
+	if err != nil {
+		appLogger.Println("ERROR While decoding log records:", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(records) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), exportTimeout)
+	defer cancel()
+	if err = exportLogs(ctx, transformToOTLP(records)); err != nil {
+		appLogger.Println("ERROR While exporting log data:", err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	appLogger.Println("Forwarded", len(records), "log records")
+	w.WriteHeader(http.StatusOK)
+}
+
+// decodeRecords accepts a JSON array of records, an Event Hub message with a "records" array or a single record
+func decodeRecords(body []byte) ([]map[string]interface{}, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, errors.New("empty request body")
+	}
+
+	if body[0] == '[' {
+		var records []map[string]interface{}
+		if err := json.Unmarshal(body, &records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+
+	var message map[string]interface{}
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil, err
+	}
+
+	rawRecords, exists := message["records"]
+	if !exists {
+		return []map[string]interface{}{message}, nil
+	}
+
+	items, ok := rawRecords.([]interface{})
+	if !ok {
+		return nil, errors.New("\"records\" property is not an array")
+	}
+	records := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if record, ok := item.(map[string]interface{}); ok {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// transformToOTLP groups the records by resource id, each resource is sent as a separate resourceLogs entry
+func transformToOTLP(records []map[string]interface{}) exportLogsRequest {
+	groups := map[string][]map[string]interface{}{}
+	for _, record := range records {
+		resourceId := getString(record, "resourceId")
+		groups[resourceId] = append(groups[resourceId], record)
+	}
+
+	resourceIds := make([]string, 0, len(groups))
+	for resourceId := range groups {
+		resourceIds = append(resourceIds, resourceId)
+	}
+	sort.Strings(resourceIds)
+
+	request := exportLogsRequest{ResourceLogs: make([]resourceLogs, 0, len(groups))}
+	for _, resourceId := range resourceIds {
+		group := groups[resourceId]
+		logRecords := make([]logRecord, 0, len(group))
+		for _, record := range group {
+			logRecords = append(logRecords, transformRecord(record))
+		}
+
+		request.ResourceLogs = append(request.ResourceLogs, resourceLogs{
+			Resource: map[string][]keyValue{
+				"attributes": resourceAttributes(resourceId, getString(group[0], "location")),
+			},
+			ScopeLogs: []scopeLogs{
+				{
+					Scope:      map[string]string{"name": scopeName},
+					LogRecords: logRecords,
+				},
+			},
+		})
+	}
+	return request
+}
+
+func resourceAttributes(resourceId, location string) []keyValue {
+	attributes := []keyValue{stringAttribute("cloud.provider", cloudProviderAzure)}
+	if resourceId != "" {
+		attributes = append(attributes, stringAttribute("cloud.resource_id", resourceId))
+		attributes = append(attributes, stringAttribute("service.instance.id", resourceId))
+	}
+	if platform := cloudPlatform(resourceId); platform != "" {
+		attributes = append(attributes, stringAttribute("cloud.platform", platform))
+	}
+	if location != "" {
+		attributes = append(attributes, stringAttribute("cloud.region", location))
+	}
+	return attributes
+}
+
+// cloudPlatform derives cloud.platform from the resource provider type, e.g.
+// /SUBSCRIPTIONS/{id}/RESOURCEGROUPS/{group}/PROVIDERS/MICROSOFT.COMPUTE/VIRTUALMACHINES/{name}
+func cloudPlatform(resourceId string) string {
+	parts := strings.Split(strings.ToLower(resourceId), "/")
+	for i, part := range parts {
+		if part == "providers" && i+2 < len(parts) {
+			return cloudPlatforms[parts[i+1]+"/"+parts[i+2]]
+		}
+	}
+	return ""
+}
+
+func transformRecord(record map[string]interface{}) logRecord {
+	result := logRecord{Body: recursiveMap(record)}
+
+	for _, key := range []string{"time", "TimeGenerated", "timeStamp"} {
+		if timestamp, err := time.Parse(time.RFC3339Nano, getString(record, key)); err == nil {
+			result.TimeUnixNano = fmt.Sprint(timestamp.UnixNano())
+			break
+		}
+	}
+
+	for _, key := range []string{"level", "Level", "SeverityLevel"} {
+		if level := getString(record, key); level != "" {
+			result.SeverityText = level
+			result.SeverityNumber = mapSeverity(level)
+			break
+		}
+	}
+
+	if category := getString(record, "category"); category != "" {
+		result.Attributes = append(result.Attributes, stringAttribute("azure.log.category", category))
+	}
+	if operationName := getString(record, "operationName"); operationName != "" {
+		result.Attributes = append(result.Attributes, stringAttribute("azure.operation.name", operationName))
+	}
+	return result
+}
+
+// mapSeverity maps Azure log levels to OTLP severity numbers
+func mapSeverity(level string) int {
+	switch strings.ToLower(level) {
+	case "trace", "verbose", "0":
+		return 1 // TRACE
+	case "debug":
+		return 5 // DEBUG
+	case "informational", "information", "info", "1":
+		return 9 // INFO
+	case "warning", "warn", "2":
+		return 13 // WARN
+	case "error", "3":
+		return 17 // ERROR
+	case "critical", "fatal", "4":
+		return 21 // FATAL
+	}
+	return 0 // UNSPECIFIED
+}
+
+// recursiveMap converts a decoded JSON value into an OTLP AnyValue
+func recursiveMap(value interface{}) anyValue {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := make([]keyValue, 0, len(v))
+		for _, key := range keys {
+			values = append(values, keyValue{Key: key, Value: recursiveMap(v[key])})
+		}
+		return anyValue{"kvlistValue": map[string]interface{}{"values": values}}
+	case []interface{}:
+		values := make([]anyValue, 0, len(v))
+		for _, item := range v {
+			values = append(values, recursiveMap(item))
+		}
+		return anyValue{"arrayValue": map[string]interface{}{"values": values}}
+	case string:
+		return anyValue{"stringValue": v}
+	case bool:
+		return anyValue{"boolValue": v}
+	case float64:
+		if v == float64(int64(v)) {
+			return anyValue{"intValue": fmt.Sprint(int64(v))}
+		}
+		return anyValue{"doubleValue": v}
+	case nil:
+		return anyValue{}
+	}
+	return anyValue{"stringValue": fmt.Sprint(value)}
+}
+
+func exportLogs(ctx context.Context, request exportLogsRequest) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, otelEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("otlp/http endpoint responded with %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func stringAttribute(key, value string) keyValue {
+	return keyValue{Key: key, Value: anyValue{"stringValue": value}}
+}
+
+func getString(record map[string]interface{}, key string) string {
+	value, _ := record[key].(string)
+	return value
+}
@@ -0,0 +1,180 @@
+/* Copyright 2022 SolarWinds Worldwide, LLC. All rights reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at:
+*
+*	http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and limitations
+* under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const eventHubMessage = `{"records": [
+	{"time": "2022-03-01T10:15:30.1234567Z", "resourceId": "/SUBSCRIPTIONS/0000/RESOURCEGROUPS/RG/PROVIDERS/MICROSOFT.COMPUTE/VIRTUALMACHINES/VM1", "location": "westeurope", "category": "Administrative", "operationName": "MICROSOFT.COMPUTE/VIRTUALMACHINES/START/ACTION", "level": "Warning", "properties": {"statusCode": 200, "durationMs": 12.5, "tags": ["a", "b"], "succeeded": true}},
+	{"time": "2022-03-01T10:15:31Z", "resourceId": "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Web/sites/app1", "level": "Error"}
+]}`
+
+func TestDecodeRecords(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		count int
+		err   bool
+	}{
+		{"array", `[{"level": "Error"}, {"level": "Informational"}]`, 2, false},
+		{"event hub message", eventHubMessage, 2, false},
+		{"single record", `{"level": "Error"}`, 1, false},
+		{"empty body", ``, 0, true},
+		{"invalid json", `[{`, 0, true},
+		{"records not array", `{"records": "x"}`, 0, true},
+	}
+
+	for _, test := range tests {
+		records, err := decodeRecords([]byte(test.body))
+		if test.err != (err != nil) {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+		if len(records) != test.count {
+			t.Errorf("%s: expected %d records, got %d", test.name, test.count, len(records))
+		}
+	}
+}
+
+func TestMapSeverity(t *testing.T) {
+	tests := map[string]int{
+		"Verbose":       1,
+		"Debug":         5,
+		"Informational": 9,
+		"Warning":       13,
+		"Error":         17,
+		"Critical":      21,
+		"unknown":       0,
+	}
+
+	for level, expected := range tests {
+		if actual := mapSeverity(level); actual != expected {
+			t.Errorf("%s: expected severity %d, got %d", level, expected, actual)
+		}
+	}
+}
+
+func TestCloudPlatform(t *testing.T) {
+	tests := map[string]string{
+		"/SUBSCRIPTIONS/0000/RESOURCEGROUPS/RG/PROVIDERS/MICROSOFT.COMPUTE/VIRTUALMACHINES/VM1":           "azure_vm",
+		"/subscriptions/0000/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks1": "azure_aks",
+		"/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Web/sites/app1":                        "azure_app_service",
+		"/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Sql/servers/db1":                       "",
+		"": "",
+	}
+
+	for resourceId, expected := range tests {
+		if actual := cloudPlatform(resourceId); actual != expected {
+			t.Errorf("%s: expected platform %q, got %q", resourceId, expected, actual)
+		}
+	}
+}
+
+func TestHandleLogs(t *testing.T) {
+	var received exportLogsRequest
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	otelEndpoint = server.URL
+	apiKey = "test-key"
+
+	recorder := httptest.NewRecorder()
+	handleLogs(recorder, httptest.NewRequest(http.MethodPost, functionRoute, strings.NewReader(eventHubMessage)))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	if authorization != "Bearer test-key" {
+		t.Errorf("unexpected authorization header %q", authorization)
+	}
+	if len(received.ResourceLogs) != 2 {
+		t.Fatalf("expected 2 resource logs, got %d", len(received.ResourceLogs))
+	}
+
+	vm := received.ResourceLogs[0]
+	attributes := map[string]string{}
+	for _, attribute := range vm.Resource["attributes"] {
+		attributes[attribute.Key], _ = attribute.Value["stringValue"].(string)
+	}
+	for key, expected := range map[string]string{
+		"cloud.provider": "azure",
+		"cloud.platform": "azure_vm",
+		"cloud.region":   "westeurope",
+	} {
+		if attributes[key] != expected {
+			t.Errorf("%s: expected %q, got %q", key, expected, attributes[key])
+		}
+	}
+
+	record := vm.ScopeLogs[0].LogRecords[0]
+	if record.SeverityNumber != 13 || record.SeverityText != "Warning" {
+		t.Errorf("unexpected severity %d %s", record.SeverityNumber, record.SeverityText)
+	}
+	if record.TimeUnixNano != "1646129730123456700" {
+		t.Errorf("unexpected timestamp %s", record.TimeUnixNano)
+	}
+	if _, ok := record.Body["kvlistValue"]; !ok {
+		t.Errorf("expected kvlist body, got %v", record.Body)
+	}
+
+	app := received.ResourceLogs[1].ScopeLogs[0].LogRecords[0]
+	if app.SeverityNumber != 17 {
+		t.Errorf("expected error severity, got %d", app.SeverityNumber)
+	}
+}
+
+func TestHandleLogsExportFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	otelEndpoint = server.URL
+
+	recorder := httptest.NewRecorder()
+	handleLogs(recorder, httptest.NewRequest(http.MethodPost, functionRoute, strings.NewReader(`[{"level": "Error"}]`)))
+
+	if recorder.Code != http.StatusBadGateway {
+		t.Errorf("expected status 502, got %d", recorder.Code)
+	}
+}
+
+func TestHandleLogsInvalidRequest(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	handleLogs(recorder, httptest.NewRequest(http.MethodGet, functionRoute, nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	handleLogs(recorder, httptest.NewRequest(http.MethodPost, functionRoute, strings.NewReader("{")))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", recorder.Code)
+	}
+}